@@ -0,0 +1,199 @@
+// Package cli 为具体的配置类型提供 validate、print、schema、env、docs 子命令
+//
+// 由于配置类型 T 必须在编译期确定，每个应用需要一个很小的 main 包：
+//
+//	func main() {
+//		cli.Main[config.Config]("myapp")
+//	}
+//
+// 该 main 包可以通过 go run github.com/oy3o/conf/cmd/conf 生成。
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/oy3o/conf"
+	"github.com/oy3o/conf/validator"
+	"github.com/spf13/pflag"
+)
+
+const usage = `Usage: %s <command> [flags]
+
+Commands:
+  validate <file>   run the full Load pipeline against a config file
+  print [file]      print the effective config (secrets redacted)
+  schema            print the JSON Schema of the config
+  env               list every environment variable the app reads
+  docs [-o file]    generate the Markdown configuration reference
+`
+
+// Main 执行子命令并以其返回码退出进程
+func Main[T any](appName string, opts ...conf.Option) {
+	os.Exit(Run[T](appName, os.Args[1:], os.Stdout, os.Stderr, opts...))
+}
+
+// Run 执行子命令并返回进程退出码: 0 成功，1 配置无效，2 用法错误
+func Run[T any](appName string, args []string, stdout, stderr io.Writer, opts ...conf.Option) int {
+	if len(args) == 0 {
+		fmt.Fprintf(stderr, usage, appName)
+		return 2
+	}
+
+	cmd, args := args[0], args[1:]
+	fs := pflag.NewFlagSet(cmd, pflag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var err error
+	switch cmd {
+	case "validate":
+		if err = fs.Parse(args); err != nil {
+			return 2
+		}
+		if fs.NArg() != 1 {
+			fmt.Fprintf(stderr, "usage: %s validate <file>\n", appName)
+			return 2
+		}
+		err = runValidate[T](appName, fs.Arg(0), stdout, opts)
+
+	case "print":
+		format := fs.String("format", "yaml", "output format (yaml, json, toml)")
+		if err = fs.Parse(args); err != nil {
+			return 2
+		}
+		if fs.NArg() > 0 {
+			opts = append(opts, conf.WithConfigFile(fs.Arg(0)))
+		}
+		err = runPrint[T](appName, *format, stdout, opts)
+
+	case "schema":
+		if err = fs.Parse(args); err != nil {
+			return 2
+		}
+		var data []byte
+		if data, err = conf.JSONSchema[T](); err == nil {
+			_, err = stdout.Write(data)
+		}
+
+	case "env":
+		if err = fs.Parse(args); err != nil {
+			return 2
+		}
+		err = runEnv[T](appName, stdout, opts)
+
+	case "docs":
+		out := fs.StringP("output", "o", "", "output file (default stdout)")
+		if err = fs.Parse(args); err != nil {
+			return 2
+		}
+		err = runDocs[T](appName, *out, stdout, opts)
+
+	case "help", "-h", "--help":
+		fmt.Fprintf(stdout, usage, appName)
+		return 0
+
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n\n", cmd)
+		fmt.Fprintf(stderr, usage, appName)
+		return 2
+	}
+
+	if err != nil {
+		printError(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func runValidate[T any](appName, file string, stdout io.Writer, opts []conf.Option) error {
+	// 一次性报告所有阶段的问题，避免在 CI 中反复修复、重跑
+	opts = append(opts, conf.WithConfigFile(file), conf.WithAllErrors())
+	if _, err := conf.Load[T](appName, opts...); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "%s: ok\n", file)
+	return nil
+}
+
+func runPrint[T any](appName, format string, stdout io.Writer, opts []conf.Option) error {
+	cfg, err := conf.Load[T](appName, opts...)
+	if err != nil {
+		return err
+	}
+	data, err := conf.Dump(cfg, format)
+	if err != nil {
+		return err
+	}
+	_, err = stdout.Write(data)
+	return err
+}
+
+func runEnv[T any](appName string, stdout io.Writer, opts []conf.Option) error {
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tKEY\tSTRICT")
+	for _, env := range conf.EnvVars[T](appName, opts...) {
+		strict := ""
+		if env.Strict {
+			strict = "strict"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", strings.Join(env.Names, ", "), env.Key, strict)
+	}
+	return w.Flush()
+}
+
+// runDocs 输出 Markdown 配置参考，指定 out 时写入文件 (便于 go:generate 保持文档同步)
+func runDocs[T any](appName, out string, stdout io.Writer, opts []conf.Option) error {
+	data, err := conf.Markdown[T](appName, opts...)
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		_, err = stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(out, data, 0o644)
+}
+
+// printError 逐行输出验证错误，其余错误原样输出；聚合错误按阶段依次输出
+func printError(w io.Writer, err error) {
+	var lerr *conf.LoadError
+	if errors.As(err, &lerr) {
+		for _, e := range lerr.Errors {
+			fmt.Fprintf(w, "[%s] ", e.Stage)
+			printError(w, e.Err)
+		}
+		return
+	}
+
+	var verr *validator.ValidationError
+	if !errors.As(err, &verr) {
+		fmt.Fprintln(w, err)
+		return
+	}
+
+	fmt.Fprintln(w, "validation failed:")
+	if len(verr.Fields) > 0 {
+		for _, f := range verr.Fields {
+			if loc := f.Location(); loc != "" {
+				fmt.Fprintf(w, "  %s: %s: %s\n", f.Path, f.Message, loc)
+			} else {
+				fmt.Fprintf(w, "  %s: %s\n", f.Path, f.Message)
+			}
+		}
+		return
+	}
+
+	keys := make([]string, 0, len(verr.Errors))
+	for key := range verr.Errors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(w, "  %s: %s\n", key, verr.Errors[key])
+	}
+}
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type DBConfig struct {
+	Host     string `mapstructure:"host" validate:"required"`
+	Port     int    `mapstructure:"port" validate:"min=1024" default:"3306"`
+	Password string `mapstructure:"password" env:"strict"`
+}
+
+type Config struct {
+	Name string   `mapstructure:"name" default:"svc"`
+	DB   DBConfig `mapstructure:"db"`
+}
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func run(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := Run[Config]("cliapp", args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun_Validate(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		path := writeConfig(t, "db:\n  host: localhost\n")
+		code, stdout, stderr := run("validate", path)
+		if code != 0 || !strings.Contains(stdout, "ok") {
+			t.Errorf("Expected success, got code %d, stderr: %s", code, stderr)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		path := writeConfig(t, "db:\n  port: 80\n")
+		code, _, stderr := run("validate", path)
+		if code != 1 {
+			t.Fatalf("Expected exit code 1, got %d", code)
+		}
+		// 每个字段单独一行，且按键名排序
+		if !strings.Contains(stderr, "  db.host: ") || !strings.Contains(stderr, "  db.port: ") ||
+			strings.Index(stderr, "db.host") > strings.Index(stderr, "db.port") {
+			t.Errorf("Expected per-field validation errors, got: %s", stderr)
+		}
+	})
+
+	t.Run("All Stages", func(t *testing.T) {
+		path := writeConfig(t, "db:\n  hots: localhost\n  port: 80\n")
+		code, _, stderr := run("validate", path)
+		if code != 1 {
+			t.Fatalf("Expected exit code 1, got %d", code)
+		}
+		if !strings.Contains(stderr, "[unknown_keys]") || !strings.Contains(stderr, "[validate]") {
+			t.Errorf("Expected unknown key and validation errors in one run, got: %s", stderr)
+		}
+	})
+
+	t.Run("Missing File", func(t *testing.T) {
+		code, _, _ := run("validate", filepath.Join(t.TempDir(), "missing.yaml"))
+		if code != 1 {
+			t.Errorf("Expected exit code 1 for missing file, got %d", code)
+		}
+	})
+}
+
+func TestRun_Print(t *testing.T) {
+	os.Setenv("CLIAPP_DB_PASSWORD", "hunter2")
+	defer os.Unsetenv("CLIAPP_DB_PASSWORD")
+
+	path := writeConfig(t, "db:\n  host: localhost\n")
+	code, stdout, stderr := run("print", "--format", "json", path)
+	if code != 0 {
+		t.Fatalf("Expected success, got code %d, stderr: %s", code, stderr)
+	}
+	if !strings.Contains(stdout, `"host": "localhost"`) || strings.Contains(stdout, "hunter2") {
+		t.Errorf("Expected redacted JSON output, got: %s", stdout)
+	}
+}
+
+func TestRun_SchemaAndEnv(t *testing.T) {
+	code, stdout, _ := run("schema")
+	if code != 0 || !strings.Contains(stdout, `"$schema"`) {
+		t.Errorf("Expected JSON Schema output, got code %d: %s", code, stdout)
+	}
+
+	code, stdout, _ = run("env")
+	if code != 0 {
+		t.Fatalf("Expected success, got code %d", code)
+	}
+	for _, want := range []string{"CLIAPP_NAME", "CLIAPP_DB_HOST", "strict"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("Expected env listing to contain %q, got: %s", want, stdout)
+		}
+	}
+}
+
+func TestRun_Docs(t *testing.T) {
+	code, stdout, _ := run("docs")
+	if code != 0 || !strings.Contains(stdout, "`db.port`") || !strings.Contains(stdout, "`CLIAPP_DB_HOST`") {
+		t.Errorf("Expected Markdown reference, got code %d: %s", code, stdout)
+	}
+
+	path := filepath.Join(t.TempDir(), "CONFIG.md")
+	if code, _, stderr := run("docs", "-o", path); code != 0 {
+		t.Fatalf("Expected success, got code %d, stderr: %s", code, stderr)
+	}
+	if data, err := os.ReadFile(path); err != nil || !strings.Contains(string(data), "`db.password`") {
+		t.Errorf("Expected reference written to file, got %q (%v)", data, err)
+	}
+}
+
+func TestRun_Usage(t *testing.T) {
+	if code, _, _ := run(); code != 2 {
+		t.Errorf("Expected usage exit code 2, got %d", code)
+	}
+	if code, _, stderr := run("bogus"); code != 2 || !strings.Contains(stderr, "unknown command") {
+		t.Errorf("Expected unknown command error, got %d: %s", code, stderr)
+	}
+}
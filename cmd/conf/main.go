@@ -0,0 +1,97 @@
+// Command conf 为应用的配置类型生成一个小型 main 包，提供 validate、print、schema、env、docs 子命令
+//
+// 用法:
+//
+//	go run github.com/oy3o/conf/cmd/conf -pkg github.com/me/app/internal/config -type Config -app myapp -o ./cmd/myapp-conf/main.go
+//
+// 也可以写在 go:generate 指令中，使生成的工具随配置结构体一起更新。
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"text/template"
+)
+
+var mainTemplate = template.Must(template.New("main").Parse(`// Code generated by github.com/oy3o/conf/cmd/conf; DO NOT EDIT.
+
+package main
+
+import (
+	"github.com/oy3o/conf/cli"
+	{{.Alias}} "{{.Pkg}}"
+)
+
+func main() {
+	cli.Main[{{.Alias}}.{{.Type}}]("{{.App}}")
+}
+`))
+
+// params 是生成 main 包所需的参数
+type params struct {
+	Pkg   string // 配置类型所在包的导入路径
+	Alias string // 导入别名
+	Type  string // 配置类型名
+	App   string // appName，决定环境变量前缀
+}
+
+// generate 渲染并格式化 main 包源码
+func generate(p params) ([]byte, error) {
+	if p.Pkg == "" || p.Type == "" || p.App == "" {
+		return nil, fmt.Errorf("-pkg, -type and -app are required")
+	}
+	if p.Alias == "" {
+		p.Alias = "config"
+	}
+
+	var buf bytes.Buffer
+	if err := mainTemplate.Execute(&buf, p); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+func main() {
+	var (
+		p   params
+		out string
+	)
+	flag.StringVar(&p.Pkg, "pkg", "", "import path of the package declaring the config type")
+	flag.StringVar(&p.Type, "type", "Config", "name of the config type")
+	flag.StringVar(&p.App, "app", "", "application name passed to conf.Load (env prefix)")
+	flag.StringVar(&out, "o", "", "output file (default stdout)")
+	flag.Parse()
+
+	if p.App == "" && p.Pkg != "" {
+		p.App = path.Base(p.Pkg)
+	}
+
+	src, err := generate(p)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "conf:", err)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := write(out, src); err != nil {
+		fmt.Fprintln(os.Stderr, "conf:", err)
+		os.Exit(1)
+	}
+}
+
+func write(out string, src []byte) error {
+	if out == "" {
+		_, err := io.Copy(os.Stdout, bytes.NewReader(src))
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(out, src, 0o644)
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	src, err := generate(params{Pkg: "example.com/app/internal/config", Type: "Config", App: "myapp"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	out := string(src)
+	for _, want := range []string{
+		"// Code generated by github.com/oy3o/conf/cmd/conf; DO NOT EDIT.",
+		`config "example.com/app/internal/config"`,
+		`cli.Main[config.Config]("myapp")`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected generated source to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestGenerate_MissingParams(t *testing.T) {
+	if _, err := generate(params{Type: "Config"}); err == nil {
+		t.Error("Expected error when -pkg and -app are missing")
+	}
+}
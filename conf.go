@@ -1,12 +1,14 @@
 package conf
 
 import (
+	"context"
 	"fmt"
-	"strings"
+	"reflect"
 
 	"github.com/go-viper/mapstructure/v2"
 	"github.com/mcuadros/go-defaults"
 	"github.com/oy3o/conf/validator"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
@@ -21,9 +23,44 @@ func MustLoad[T any](appName string, opts ...Option) *T {
 
 // Load 加载并验证配置
 func Load[T any](appName string, opts ...Option) (*T, error) {
-	o := defaultOptions()
-	for _, opt := range opts {
-		opt(o)
+	return LoadContext[T](context.Background(), appName, opts...)
+}
+
+// LoadContext 与 Load 相同，ctx 会传给验证阶段: 配置类型 (或深度验证时的嵌套值) 实现
+// validator.ContextValidatable 时以 ctx 调用 Validate(ctx)，validator.RegisterRuleCtx 注册的规则同样收到 ctx。
+// 各阶段之间检查 ctx，取消或超时时返回 ctx.Err()。
+func LoadContext[T any](ctx context.Context, appName string, opts ...Option) (*T, error) {
+	cfg, _, err := load[T](ctx, appName, newOptions(opts))
+	return cfg, err
+}
+
+// loadState 记录一次加载过程中的中间状态，供来源报告等功能使用
+type loadState struct {
+	v      *viper.Viper
+	naming envNaming
+	files  []string       // 按合并顺序实际读取的配置文件
+	flags  *pflag.FlagSet // 可能为 nil
+	typ    reflect.Type   // 配置结构体类型
+
+	configs   []*viper.Viper        // 与 files 一一对应，各文件单独解析的内容
+	types     []string              // 与 files 一一对应，解析各文件时使用的格式
+	sources   map[string]Source     // 加载时记录的每个叶子配置项的值来源
+	positions []map[string]position // 与 files 一一对应，出错时才按需解析
+}
+
+// registerKeys 让 Viper 知道每个叶子配置项
+// Unmarshal 只遍历已知 key，未登记时配置文件中的空列表、空 map 等值会被丢弃；
+// nil 默认值本身不参与合并，结构体默认值仍由 default 标签提供
+func (s *loadState) registerKeys() {
+	for _, leaf := range collectLeaves(s.typ) {
+		s.v.SetDefault(leaf.key(), nil)
+	}
+}
+
+// load 执行完整的加载流程，Load、LoadWithReport 与 Watcher 共用
+func load[T any](ctx context.Context, appName string, o *options) (*T, *loadState, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
 	}
 
 	var cfg T
@@ -33,48 +70,103 @@ func Load[T any](appName string, opts ...Option) (*T, error) {
 
 	// 2. 初始化 Viper
 	v := viper.New()
-	v.SetConfigName(o.fileName)
-	v.SetConfigType(o.fileType)
-	for _, path := range o.searchPaths {
-		v.AddConfigPath(path)
+	naming := o.envNaming(appName)
+	state := &loadState{v: v, naming: naming, flags: o.flags, typ: reflect.TypeOf(cfg), sources: make(map[string]Source)}
+	state.registerKeys()
+
+	// 3. 读取环境变量 (含 <NAME>_FILE)
+	// 规则: appName="myapp", field="db.host" -> "MYAPP_DB_HOST" (前缀和分隔符可通过 Option 调整)
+	// 不使用 Viper 的 SetEnvPrefix/AutomaticEnv: 其推导的变量名固定以 "_" 连接前缀，且优先于声明的变量名；
+	// 每个叶子配置项都由 resolveEnvs 按 envNaming 显式读取
+	if err := state.resolveEnvs(); err != nil {
+		return nil, nil, err
 	}
 
-	// 3. 绑定环境变量
-	// 规则: appName="myapp", field="db.host" -> "MYAPP_DB_HOST"
-	v.SetEnvPrefix(appName)
-	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-	v.AutomaticEnv()
+	// 3.1 绑定命令行参数
+	if err := state.bindFlags(); err != nil {
+		return nil, nil, fmt.Errorf("bind flags: %w", err)
+	}
 
 	// 4. 读取文件 (忽略文件未找到错误，支持纯 Env 运行)
-	if err := v.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			return nil, fmt.Errorf("read config file: %w", err)
+	if path, configType, ok := o.baseConfigFile(); ok {
+		if err := state.mergeFile(path, configType); err != nil {
+			return nil, nil, fmt.Errorf("read config file: %w", err)
+		}
+	}
+
+	// 4.1 叠加 profile 配置 (config.<env>.yaml, config.local.yaml ...)，多余字段检查作用于合并结果
+	if err := state.mergeProfiles(o); err != nil {
+		return nil, nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	state.recordDefaults()
+
+	// 4.2 多余字段检查，给出来源文件和拼写建议
+	errs := &errorCollector{all: o.allErrors}
+	unknownErr := checkUnknownKeys(state, o.locale)
+	if unknownErr != nil {
+		if err := errs.add(StageUnknownKeys, unknownErr); err != nil {
+			return nil, nil, err
 		}
 	}
 
 	// 5. 解析到结构体 (严格模式：防止拼写错误)
+	var decodeErr error
 	if err := v.Unmarshal(&cfg, func(c *mapstructure.DecoderConfig) {
-		c.TagName = "mapstructure"
-		c.ErrorUnused = true // 关键：配置文件有多余字段直接报错
+		// 与 resolveKeyName 的优先级保持一致: mapstructure > yaml > json > toml
+		c.TagName = "mapstructure,yaml,json,toml"
+		// 关键：配置文件有多余字段直接报错 (已由 checkUnknownKeys 报告时不再重复)
+		c.ErrorUnused = unknownErr == nil
+		c.DecodeHook = mapstructure.ComposeDecodeHookFunc(c.DecodeHook, secretDecodeHook)
 	}); err != nil {
-		return nil, fmt.Errorf("unmarshal config: %w", err)
+		decodeErr = state.decodeErrors(err)
+		if err := errs.add(StageDecode, fmt.Errorf("unmarshal config: %w", decodeErr)); err != nil {
+			return nil, nil, err
+		}
 	}
 
 	// 6. 生产环境来源检查 (Env Strict)
-	if err := checkEnvStrict(appName, &cfg); err != nil {
-		return nil, err
+	if err := checkEnvStrict(naming, state.sources, &cfg); err != nil {
+		if err := errs.add(StageEnvStrict, err); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := checkStrictFlags(o.flags, &cfg); err != nil {
+		if err := errs.add(StageEnvStrict, err); err != nil {
+			return nil, nil, err
+		}
 	}
 
 	// 7. 数据内容验证 (集成新 Validator)
-	val, err := validator.New(o.locale) // 初始化验证器
+	val, err := o.newValidator() // 初始化验证器 (或使用 WithValidator 共享的实例)
 	if err != nil {
-		return nil, fmt.Errorf("init validator: %w", err)
+		return nil, nil, fmt.Errorf("init validator: %w", err)
 	}
+	registerSecretTypes(val, reflect.TypeOf(cfg), o.validator != nil)
 
-	// 执行验证 (混合模式：自动识别 Interface 或 Tag)
-	if err := val.Validate(&cfg); err != nil {
-		return nil, err // 直接返回 validator 的友好错误信息
+	// 执行验证 (混合模式：自动识别 Interface 或 Tag；深度模式两者都执行)
+	validate := val.ValidateCtx
+	if o.deepValidation {
+		validate = val.ValidateDeepCtx
+	}
+	// 敏感字段的 {value} 只在本次验证中脱敏，不修改 (可能共享的) 验证器
+	if err := validate(validator.ContextWithRedactor(ctx, isSensitive), &cfg); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, nil, ctxErr
+		}
+		state.annotateFields(err)
+		// 解码失败的字段保持零值，其验证错误只是噪音
+		if err := dropDecodedFields(err, decodeErr); err != nil {
+			if err := errs.add(StageValidate, err); err != nil {
+				return nil, nil, err // 直接返回 validator 的友好错误信息
+			}
+		}
 	}
 
-	return &cfg, nil
+	if err := errs.err(); err != nil {
+		return nil, nil, err
+	}
+	return &cfg, state, nil
 }
@@ -1,11 +1,17 @@
 package conf
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/oy3o/conf/validator"
 )
 
 // ----------------------------------------------------------------
@@ -268,9 +274,10 @@ func TestStrictEnv_Complex(t *testing.T) {
 
 	// 场景 1: 缺少 Password (应该报错，且 key 不含 omitempty)
 	t.Run("Missing Password", func(t *testing.T) {
-		os.Unsetenv("MYAPP_PASSWORD")
 		cfg := &StrictConfig{Sub: &StrictSub{ApiKey: "123"}}
-		err := checkEnvStrict("myapp", cfg)
+		// ApiKey 来自环境变量，Password 没有记录来源
+		sources := map[string]Source{"sub.api_key": {Kind: SourceEnv, Name: "MYAPP_SUB_API_KEY"}}
+		err := checkEnvStrict(envNaming{prefix: "myapp", sep: "_"}, sources, cfg)
 		if err == nil {
 			t.Fatal("Expected error")
 		}
@@ -284,11 +291,14 @@ func TestStrictEnv_Complex(t *testing.T) {
 
 	// 场景 2: 缺少嵌套指针里的 ApiKey
 	t.Run("Missing Nested Ptr Env", func(t *testing.T) {
-		os.Setenv("MYAPP_PASSWORD", "pass") // 满足第一层
-		os.Unsetenv("MYAPP_SUB_API_KEY")    // 缺失第二层
+		// 满足第一层，第二层的值来自配置文件
+		sources := map[string]Source{
+			"password":    {Kind: SourceEnv, Name: "MYAPP_PASSWORD"},
+			"sub.api_key": {Kind: SourceFile, Name: "config.yaml"},
+		}
 
 		cfg := &StrictConfig{Sub: &StrictSub{}} // 指针不为 nil
-		err := checkEnvStrict("myapp", cfg)
+		err := checkEnvStrict(envNaming{prefix: "myapp", sep: "_"}, sources, cfg)
 		if err == nil {
 			t.Fatal("Expected error for nested pointer strict field")
 		}
@@ -327,6 +337,149 @@ func TestLoad_HybridValidation(t *testing.T) {
 	}
 }
 
+type DeepTLS struct {
+	Cert string `mapstructure:"cert"`
+	Key  string `mapstructure:"key"`
+}
+
+func (t *DeepTLS) Validate() error {
+	if (t.Cert == "") != (t.Key == "") {
+		return fmt.Errorf("cert and key must be set together")
+	}
+	return nil
+}
+
+type DeepConfig struct {
+	Name string   `mapstructure:"name" validate:"required"`
+	TLS  *DeepTLS `mapstructure:"tls"`
+}
+
+func (c *DeepConfig) Validate() error {
+	return nil
+}
+
+func TestLoad_DeepValidation(t *testing.T) {
+	configDir := createConfigFile(t, "config.yaml", "tls:\n  cert: cert.pem\n")
+
+	// 默认模式只调用根类型的 Validate()
+	if _, err := Load[DeepConfig]("deepapp", WithSearchPaths(configDir)); err != nil {
+		t.Fatalf("Expected fast path to pass, got %v", err)
+	}
+
+	_, err := Load[DeepConfig]("deepapp", WithSearchPaths(configDir), WithDeepValidation(), WithLocale("en"))
+	var verr *validator.ValidationError
+	if !errors.As(err, &verr) || len(verr.Fields) != 2 {
+		t.Fatalf("Expected tag and nested errors, got %v", err)
+	}
+	if verr.Fields[0].Path != "name" || verr.Fields[1].Path != "tls" || verr.Fields[1].Tag != validator.SelfTag {
+		t.Errorf("Expected errors on name and tls, got %+v", verr.Fields)
+	}
+	// 嵌套 Validate() 的错误同样带有文件位置
+	if verr.Fields[1].Line != 1 {
+		t.Errorf("Expected tls error located at line 1, got %+v", verr.Fields[1])
+	}
+}
+
+type registryKey struct{}
+
+// Backend 通过 ctx 中的注册表检查服务是否存在
+type Backend struct {
+	Service string `mapstructure:"service"`
+}
+
+func (b Backend) Validate(ctx context.Context) error {
+	registry, _ := ctx.Value(registryKey{}).(map[string]bool)
+	if !registry[b.Service] {
+		return fmt.Errorf("unknown service %q", b.Service)
+	}
+	return nil
+}
+
+type CtxConfig struct {
+	Backends []Backend `mapstructure:"backends"`
+}
+
+func TestLoadContext(t *testing.T) {
+	configDir := createConfigFile(t, "config.yaml", "backends:\n  - service: users\n  - service: orders\n")
+	ctx := context.WithValue(context.Background(), registryKey{}, map[string]bool{"users": true})
+
+	_, err := LoadContext[CtxConfig](ctx, "ctxapp", WithSearchPaths(configDir), WithDeepValidation())
+	var verr *validator.ValidationError
+	if !errors.As(err, &verr) || len(verr.Fields) != 1 || verr.Fields[0].Path != "backends[1]" {
+		t.Fatalf("Expected error on backends[1] from ctx registry, got %v", err)
+	}
+
+	ctx = context.WithValue(context.Background(), registryKey{}, map[string]bool{"users": true, "orders": true})
+	if _, err := LoadContext[CtxConfig](ctx, "ctxapp", WithSearchPaths(configDir), WithDeepValidation()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		if _, err := LoadContext[CtxConfig](ctx, "ctxapp", WithSearchPaths(configDir)); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	})
+
+	t.Run("Deadline In Rule", func(t *testing.T) {
+		val, _ := validator.New("en")
+		val.RegisterRuleCtx("resolvable", func(ctx context.Context, fl validator.FieldLevel) bool {
+			<-ctx.Done() // 模拟超时的 DNS 查询
+			return false
+		}, nil)
+
+		type DNSConfig struct {
+			Host string `mapstructure:"host" validate:"resolvable" default:"db.internal"`
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := LoadContext[DNSConfig](ctx, "ctxapp", WithSearchPaths(t.TempDir()), WithValidator(val), WithAllErrors())
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+		}
+	})
+}
+
+// Node 引用自身类型，展开叶子配置项时不能无限递归
+type Node struct {
+	Name  string `mapstructure:"name"`
+	Child *Node  `mapstructure:"child"`
+}
+
+func TestLoad_RecursiveType(t *testing.T) {
+	configDir := createConfigFile(t, "config.yaml", "name: root\nchild:\n  name: leaf\n  child:\n    name: deep\n")
+
+	done := make(chan struct{})
+	var cfg *Node
+	var err error
+	go func() {
+		defer close(done)
+		cfg, err = Load[Node]("nodeapp", WithSearchPaths(configDir))
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Load did not return for a self-referential config type")
+	}
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Name != "root" || cfg.Child == nil || cfg.Child.Name != "leaf" || cfg.Child.Child == nil || cfg.Child.Child.Name != "deep" {
+		t.Errorf("Expected nested nodes decoded, got %+v", cfg)
+	}
+
+	// 引用自身的字段整体作为一个叶子配置项
+	var keys []string
+	for _, leaf := range collectLeaves(reflect.TypeOf(Node{})) {
+		keys = append(keys, leaf.key())
+	}
+	if fmt.Sprint(keys) != "[name child]" {
+		t.Errorf("Expected leaves [name child], got %v", keys)
+	}
+}
+
 // ----------------------------------------------------------------
 // 测试多标签支持 (Mapstructure / Json / Yaml)
 // ----------------------------------------------------------------
@@ -390,3 +543,269 @@ func TestValidator_MultiTag_FieldNames(t *testing.T) {
 		t.Errorf("Expected error message to use yaml tag 'db_port', got: %s", err.Error())
 	}
 }
+
+func TestLoad_PureEnvBinding(t *testing.T) {
+	// 场景：没有配置文件、没有默认值，字段只存在于环境变量中
+	configDir := t.TempDir()
+
+	os.Setenv("MYAPP_DATABASE_HOST", "env-host")
+	os.Setenv("MYAPP_DATABASE_PASSWORD", "env-pass")
+	defer os.Unsetenv("MYAPP_DATABASE_HOST")
+	defer os.Unsetenv("MYAPP_DATABASE_PASSWORD")
+
+	cfg, err := Load[TestConfig]("myapp", WithSearchPaths(configDir))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Database.Host != "env-host" || cfg.Database.Password != "env-pass" {
+		t.Errorf("Expected values from env, got %+v", cfg.Database)
+	}
+
+	t.Run("Nested Pointer", func(t *testing.T) {
+		os.Setenv("MYAPP_SUB_API_KEY", "key")
+		defer os.Unsetenv("MYAPP_SUB_API_KEY")
+
+		cfg, err := Load[StrictConfig]("myapp", WithSearchPaths(configDir))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if cfg.Sub == nil || cfg.Sub.ApiKey != "key" {
+			t.Errorf("Expected nested pointer populated from env, got %+v", cfg.Sub)
+		}
+	})
+}
+
+type LegacyEnvConfig struct {
+	// 兼容 PaaS 注入的变量名，按顺序回退
+	DSN  string `mapstructure:"dsn" env:"DATABASE_URL,DB_URL,strict"`
+	Port int    `mapstructure:"port" env:"PORT" default:"8080"`
+}
+
+func TestLoad_ExplicitEnvNames(t *testing.T) {
+	configDir := t.TempDir()
+
+	os.Setenv("DB_URL", "postgres://fallback")
+	os.Setenv("PORT", "9090")
+	defer os.Unsetenv("DB_URL")
+	defer os.Unsetenv("PORT")
+
+	cfg, err := Load[LegacyEnvConfig]("myapp", WithSearchPaths(configDir))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.DSN != "postgres://fallback" || cfg.Port != 9090 {
+		t.Errorf("Expected values from declared env names, got %+v", cfg)
+	}
+
+	t.Run("First Name Wins", func(t *testing.T) {
+		os.Setenv("DATABASE_URL", "postgres://primary")
+		defer os.Unsetenv("DATABASE_URL")
+
+		cfg, err := Load[LegacyEnvConfig]("myapp", WithSearchPaths(configDir))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if cfg.DSN != "postgres://primary" {
+			t.Errorf("Expected DATABASE_URL to take precedence, got %s", cfg.DSN)
+		}
+	})
+
+	t.Run("Declared Name Beats Derived", func(t *testing.T) {
+		os.Setenv("DATABASE_URL", "postgres://declared")
+		os.Setenv("MYAPP_DSN", "postgres://derived")
+		defer os.Unsetenv("DATABASE_URL")
+		defer os.Unsetenv("MYAPP_DSN")
+
+		cfg, report, err := LoadWithReport[LegacyEnvConfig]("myapp", WithSearchPaths(configDir))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if cfg.DSN != "postgres://declared" {
+			t.Errorf("Expected value from DATABASE_URL, got %s", cfg.DSN)
+		}
+		if src, _ := report.Source("dsn"); src.Name != "DATABASE_URL" {
+			t.Errorf("Expected source DATABASE_URL, got %+v", src)
+		}
+
+		// 推导出的变量名不属于声明的候选，既不生效也不满足 strict 检查
+		os.Unsetenv("DATABASE_URL")
+		os.Unsetenv("DB_URL")
+		defer os.Setenv("DB_URL", "postgres://fallback")
+		cfg, err = Load[LegacyEnvConfig]("myapp", WithSearchPaths(configDir))
+		if err != nil || cfg.DSN != "" {
+			t.Errorf("Expected derived MYAPP_DSN to be ignored, got %q (%v)", cfg.DSN, err)
+		}
+	})
+
+	t.Run("Strict Uses Declared Names", func(t *testing.T) {
+		os.Setenv("GO_ENV", "production")
+		defer os.Unsetenv("GO_ENV")
+		os.Unsetenv("DB_URL")
+		defer os.Setenv("DB_URL", "postgres://fallback")
+
+		_, err := Load[LegacyEnvConfig]("myapp", WithSearchPaths(configDir))
+		if err == nil || !strings.Contains(err.Error(), "'DATABASE_URL' or 'DB_URL'") {
+			t.Errorf("Expected strict error naming declared variables, got %v", err)
+		}
+	})
+}
+
+func TestLoad_EnvPrefixOptions(t *testing.T) {
+	configDir := createConfigFile(t, "config.yaml", "database:\n  host: \"file-host\"\n")
+
+	t.Run("Shared Prefix", func(t *testing.T) {
+		os.Setenv("SHARED_DATABASE_HOST", "shared-host")
+		defer os.Unsetenv("SHARED_DATABASE_HOST")
+
+		cfg, err := Load[TestConfig]("myapp", WithSearchPaths(configDir), WithEnvPrefix("shared"))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if cfg.Database.Host != "shared-host" {
+			t.Errorf("Expected host from SHARED_DATABASE_HOST, got %s", cfg.Database.Host)
+		}
+	})
+
+	t.Run("Empty Prefix And Separator", func(t *testing.T) {
+		os.Setenv("DATABASE__HOST", "bare-host")
+		defer os.Unsetenv("DATABASE__HOST")
+
+		cfg, err := Load[TestConfig]("myapp",
+			WithSearchPaths(configDir),
+			WithEnvPrefix(""),
+			WithEnvKeySeparator("__"),
+		)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if cfg.Database.Host != "bare-host" {
+			t.Errorf("Expected host from DATABASE__HOST, got %s", cfg.Database.Host)
+		}
+	})
+
+	t.Run("Separator Applies After Prefix", func(t *testing.T) {
+		// Viper 自带的前缀规则会得到 PROBE_DATABASE__HOST，不应生效
+		os.Setenv("PROBE_DATABASE__HOST", "viper-style")
+		defer os.Unsetenv("PROBE_DATABASE__HOST")
+
+		opts := []Option{WithSearchPaths(configDir), WithEnvPrefix("probe"), WithEnvKeySeparator("__")}
+		cfg, err := Load[TestConfig]("myapp", opts...)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if cfg.Database.Host != "file-host" {
+			t.Errorf("Expected PROBE_DATABASE__HOST to be ignored, got %s", cfg.Database.Host)
+		}
+
+		os.Setenv("PROBE__DATABASE__HOST", "documented")
+		defer os.Unsetenv("PROBE__DATABASE__HOST")
+		cfg, err = Load[TestConfig]("myapp", opts...)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if cfg.Database.Host != "documented" {
+			t.Errorf("Expected host from PROBE__DATABASE__HOST, got %s", cfg.Database.Host)
+		}
+	})
+
+	t.Run("Strict Check Honours Prefix", func(t *testing.T) {
+		os.Setenv("GO_ENV", "production")
+		defer os.Unsetenv("GO_ENV")
+
+		_, err := Load[TestConfig]("myapp", WithSearchPaths(configDir), WithEnvPrefix(""))
+		if err == nil || !strings.Contains(err.Error(), "'DATABASE_PASSWORD'") {
+			t.Errorf("Expected strict error for DATABASE_PASSWORD, got %v", err)
+		}
+	})
+}
+
+func TestLoad_EnvFileIndirection(t *testing.T) {
+	configDir := createConfigFile(t, "config.yaml", "database:\n  host: \"localhost\"\n")
+	secret := filepath.Join(t.TempDir(), "db_password")
+	if err := os.WriteFile(secret, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Setenv("MYAPP_DATABASE_PASSWORD_FILE", secret)
+	defer os.Unsetenv("MYAPP_DATABASE_PASSWORD_FILE")
+
+	cfg, err := Load[TestConfig]("myapp", WithSearchPaths(configDir))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cfg.Database.Password != "from-file" {
+		t.Errorf("Expected password read from file without newline, got %q", cfg.Database.Password)
+	}
+
+	t.Run("Satisfies Strict In Production", func(t *testing.T) {
+		os.Setenv("GO_ENV", "production")
+		defer os.Unsetenv("GO_ENV")
+
+		if _, err := Load[TestConfig]("myapp", WithSearchPaths(configDir)); err != nil {
+			t.Fatalf("Expected _FILE variable to satisfy strict check, got %v", err)
+		}
+	})
+
+	t.Run("Direct Env Wins", func(t *testing.T) {
+		os.Setenv("MYAPP_DATABASE_PASSWORD", "direct")
+		defer os.Unsetenv("MYAPP_DATABASE_PASSWORD")
+
+		cfg, err := Load[TestConfig]("myapp", WithSearchPaths(configDir))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if cfg.Database.Password != "direct" {
+			t.Errorf("Expected direct env to take precedence, got %q", cfg.Database.Password)
+		}
+	})
+
+	t.Run("Missing File", func(t *testing.T) {
+		os.Setenv("MYAPP_DATABASE_PASSWORD_FILE", filepath.Join(t.TempDir(), "missing"))
+
+		_, err := Load[TestConfig]("myapp", WithSearchPaths(configDir))
+		if err == nil || !strings.Contains(err.Error(), "MYAPP_DATABASE_PASSWORD_FILE") {
+			t.Errorf("Expected read error naming the variable, got %v", err)
+		}
+	})
+}
+
+type CustomRuleConfig struct {
+	Port int `mapstructure:"port" validate:"port"`
+}
+
+func TestLoad_WithValidator(t *testing.T) {
+	val, err := validator.New("en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = val.RegisterRule("port", func(fl validator.FieldLevel) bool {
+		p := fl.Field().Int()
+		return p > 0 && p < 65536
+	}, map[string]string{"en": "{0} must be a valid port"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configDir := createConfigFile(t, "config.yaml", "port: 70000\n")
+
+	// 同一个验证器可在多次 Load 之间复用
+	for i := 0; i < 2; i++ {
+		_, err := Load[CustomRuleConfig]("ruleapp", WithSearchPaths(configDir), WithValidator(val))
+		if err == nil || !strings.Contains(err.Error(), "port must be a valid port") {
+			t.Fatalf("Expected custom rule error, got %v", err)
+		}
+	}
+}
+
+func TestLoad_UnknownLocale(t *testing.T) {
+	configDir := createConfigFile(t, "config.yaml", "database:\n  host: h\n")
+
+	_, err := Load[TestConfig]("myapp", WithSearchPaths(configDir), WithLocale("xx"))
+	if !errors.Is(err, validator.ErrUnknownLocale) {
+		t.Errorf("Expected ErrUnknownLocale, got %v", err)
+	}
+
+	if _, err := Load[TestConfig]("myapp", WithSearchPaths(configDir), WithLocale("ja")); err != nil {
+		t.Errorf("Expected built-in ja locale, got %v", err)
+	}
+}
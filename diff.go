@@ -0,0 +1,48 @@
+package conf
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Change 描述一次重新加载中单个配置项的变化
+type Change struct {
+	Key    string // 点分隔的键路径 (与 resolveKeyName 一致)，如 "db.host"
+	Old    any    // 旧值，所在的嵌套指针为 nil 时为 nil
+	New    any    // 新值，所在的嵌套指针为 nil 时为 nil
+	Strict bool   // 字段是否标记了 env:"strict"
+}
+
+// Within 判断变化是否发生在 prefix 段内，如 c.Within("db") 匹配 "db.host"
+func (c Change) Within(prefix string) bool {
+	return c.Key == prefix || strings.HasPrefix(c.Key, prefix+".")
+}
+
+// diffConfig 按结构体声明顺序比较新旧配置，返回所有发生变化的叶子配置项
+func diffConfig[T any](old, new *T) []Change {
+	oldVal := reflect.ValueOf(old).Elem()
+	newVal := reflect.ValueOf(new).Elem()
+
+	var changes []Change
+	for _, leaf := range collectLeaves(oldVal.Type()) {
+		o, oldOK := fieldByIndex(oldVal, leaf.index)
+		n, newOK := fieldByIndex(newVal, leaf.index)
+
+		if oldOK && newOK && reflect.DeepEqual(o.Interface(), n.Interface()) {
+			continue
+		}
+		if !oldOK && !newOK {
+			continue
+		}
+
+		c := Change{Key: leaf.key(), Strict: isStrict(leaf.field)}
+		if oldOK {
+			c.Old = o.Interface()
+		}
+		if newOK {
+			c.New = n.Interface()
+		}
+		changes = append(changes, c)
+	}
+	return changes
+}
@@ -0,0 +1,80 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestDiffConfig(t *testing.T) {
+	old := &TestConfig{AppName: "a", Database: Database{Host: "h1", Port: 3306}}
+	new := &TestConfig{AppName: "a", Database: Database{Host: "h2", Port: 3306, Password: "p"}}
+
+	changes := diffConfig(old, new)
+	if len(changes) != 2 {
+		t.Fatalf("Expected 2 changes, got %d: %+v", len(changes), changes)
+	}
+
+	if changes[0].Key != "database.host" || changes[0].Old != "h1" || changes[0].New != "h2" {
+		t.Errorf("Unexpected host change: %+v", changes[0])
+	}
+	if changes[0].Strict {
+		t.Error("database.host should not be strict")
+	}
+	if changes[1].Key != "database.password" || !changes[1].Strict {
+		t.Errorf("Expected strict password change, got: %+v", changes[1])
+	}
+	if !changes[1].Within("database") || changes[1].Within("data") {
+		t.Error("Within should match whole key segments only")
+	}
+}
+
+func TestDiffConfig_NilPointer(t *testing.T) {
+	old := &StrictConfig{}
+	new := &StrictConfig{Sub: &StrictSub{ApiKey: "k"}}
+
+	changes := diffConfig(old, new)
+	if len(changes) != 1 || changes[0].Key != "sub.api_key" || changes[0].Old != nil || changes[0].New != "k" {
+		t.Fatalf("Unexpected changes: %+v", changes)
+	}
+
+	if changes := diffConfig(old, &StrictConfig{}); len(changes) != 0 {
+		t.Errorf("Expected no changes between nil pointers, got %+v", changes)
+	}
+}
+
+func TestWatch_OnChange(t *testing.T) {
+	configDir := createConfigFile(t, "config.yaml", "database:\n  host: \"a\"\n")
+
+	w, err := Watch[TestConfig]("myapp", WithSearchPaths(configDir))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer w.Close()
+
+	var (
+		mu  sync.Mutex
+		got []Change
+	)
+	w.OnChange(func(old, new *TestConfig, changes []Change) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = changes
+	})
+
+	path := filepath.Join(configDir, "config.yaml")
+	if err := os.WriteFile(path, []byte("database:\n  host: \"a\"\n  port: 5432\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// 直接触发重新加载，避免依赖文件事件的时序
+	if err := w.Reload(); err != nil {
+		t.Fatalf("Expected no reload error, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != 1 || got[0].Key != "database.port" || got[0].Old != 3306 || got[0].New != 5432 {
+		t.Fatalf("Unexpected changes: %+v", got)
+	}
+}
@@ -0,0 +1,118 @@
+package conf
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/oy3o/conf/validator"
+)
+
+// docHeaders 是参考文档的表头，按语言区分
+var docHeaders = map[string][]string{
+	"zh":    {"配置项", "环境变量", "类型", "默认值", "说明", "校验规则", "仅限环境变量"},
+	"zh_tw": {"設定項", "環境變數", "類型", "預設值", "說明", "驗證規則", "僅限環境變數"},
+	"en":    {"Key", "Environment", "Type", "Default", "Description", "Validation", "Strict"},
+	"ja":    {"キー", "環境変数", "型", "デフォルト値", "説明", "バリデーション", "環境変数のみ"},
+	"ko":    {"키", "환경 변수", "타입", "기본값", "설명", "검증 규칙", "환경 변수 전용"},
+	"de":    {"Schlüssel", "Umgebungsvariable", "Typ", "Standardwert", "Beschreibung", "Validierung", "Nur Umgebung"},
+	"fr":    {"Clé", "Variable d'environnement", "Type", "Valeur par défaut", "Description", "Validation", "Environnement uniquement"},
+	"es":    {"Clave", "Variable de entorno", "Tipo", "Valor por defecto", "Descripción", "Validación", "Solo entorno"},
+	"pt":    {"Chave", "Variável de ambiente", "Tipo", "Valor padrão", "Descrição", "Validação", "Somente ambiente"},
+}
+
+// Markdown 根据 T 生成 Markdown 格式的配置参考文档，每个配置项一行
+//
+// 列依次为: 配置项路径、环境变量名、Go 类型、默认值、desc 说明、校验规则和 env:"strict" 标记。
+// 校验规则按 WithLocale 指定的语言翻译为可读文本，环境变量名与 Load 的命名规则一致。
+// 敏感字段 (strict、secret:"true"、Secret 类型) 不输出默认值。
+func Markdown[T any](appName string, opts ...Option) ([]byte, error) {
+	o := newOptions(opts)
+	naming := o.envNaming(appName)
+
+	val, err := o.newValidator()
+	if err != nil {
+		return nil, err
+	}
+
+	headers := localized(docHeaders, o.locale)
+
+	var buf bytes.Buffer
+	writeDocRow(&buf, headers)
+	buf.WriteString("|" + strings.Repeat(" --- |", len(headers)) + "\n")
+
+	for _, leaf := range collectLeaves(reflect.TypeOf((*T)(nil)).Elem()) {
+		envs := naming.names(leaf)
+		for i, name := range envs {
+			envs[i] = "`" + name + "`"
+		}
+
+		def := ""
+		if d, ok := leaf.field.Tag.Lookup("default"); ok && d != "" && !isSensitive(leaf.field) {
+			def = "`" + d + "`"
+		}
+
+		strict := ""
+		if isStrict(leaf.field) {
+			strict = "✓"
+		}
+
+		writeDocRow(&buf, []string{
+			"`" + leaf.key() + "`",
+			strings.Join(envs, "<br>"),
+			"`" + leaf.field.Type.String() + "`",
+			def,
+			leaf.field.Tag.Get("desc"),
+			strings.Join(describeRules(val, leaf), "<br>"),
+			strict,
+		})
+	}
+	return buf.Bytes(), nil
+}
+
+// describeRules 翻译字段的 validate 规则，dive 之后的规则作用于元素，以 "[]" 标注
+func describeRules(val *validator.Validator, leaf leafField) []string {
+	rules, itemRules := splitDive(leaf.field.Tag.Get("validate"))
+
+	typ := leaf.field.Type
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if isSecretType(typ) {
+		typ = reflect.New(typ).Interface().(secretSetter).innerType()
+	}
+
+	out := describeRuleList(val, leaf.key(), rules, typ.Kind(), "")
+	if itemRules != nil && (typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array || typ.Kind() == reflect.Map) {
+		elem := typ.Elem()
+		for elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+		out = append(out, describeRuleList(val, leaf.key()+"[]", itemRules, elem.Kind(), "[] ")...)
+	}
+	return out
+}
+
+func describeRuleList(val *validator.Validator, field string, rules []string, kind reflect.Kind, prefix string) []string {
+	var out []string
+	for _, rule := range rules {
+		tag, param, _ := strings.Cut(rule, "=")
+		if tag == "" || tag == "omitempty" {
+			continue
+		}
+		out = append(out, prefix+val.Describe(field, tag, param, kind))
+	}
+	return out
+}
+
+// writeDocRow 输出一行表格，转义单元格中的竖线和换行
+func writeDocRow(buf *bytes.Buffer, cells []string) {
+	buf.WriteString("|")
+	for _, cell := range cells {
+		cell = strings.ReplaceAll(cell, "|", `\|`)
+		cell = strings.ReplaceAll(cell, "\n", " ")
+		fmt.Fprintf(buf, " %s |", cell)
+	}
+	buf.WriteByte('\n')
+}
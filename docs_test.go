@@ -0,0 +1,63 @@
+package conf
+
+import (
+	"strings"
+	"testing"
+)
+
+type DocsConfig struct {
+	Name     string   `mapstructure:"name" default:"svc" desc:"service name"`
+	Port     int      `mapstructure:"port" validate:"min=1024" default:"8080"`
+	Mode     string   `mapstructure:"mode" validate:"oneof=dev prod"`
+	Hosts    []string `mapstructure:"hosts" validate:"min=1,dive,required"`
+	Password string   `mapstructure:"password" env:"strict" default:"changeme"`
+}
+
+func TestMarkdown(t *testing.T) {
+	t.Run("English", func(t *testing.T) {
+		data, err := Markdown[DocsConfig]("docsapp", WithLocale("en"))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		out := string(data)
+
+		for _, want := range []string{
+			"| Key | Environment | Type | Default | Description | Validation | Strict |",
+			"| `name` | `DOCSAPP_NAME` | `string` | `svc` | service name |  |  |",
+			"port must be 1,024 or greater",
+			"mode must be one of [dev prod]",
+			"hosts must contain at least 1 item",
+			"[] hosts[] is a required field",
+		} {
+			if !strings.Contains(out, want) {
+				t.Errorf("Expected output to contain %q, got:\n%s", want, out)
+			}
+		}
+
+		// strict 字段标记 ✓，且不泄露默认值
+		if !strings.Contains(out, "| `password` | `DOCSAPP_PASSWORD` | `string` |  |  |  | ✓ |") {
+			t.Errorf("Expected strict password row without default, got:\n%s", out)
+		}
+	})
+
+	t.Run("Chinese", func(t *testing.T) {
+		data, err := Markdown[DocsConfig]("docsapp")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		out := string(data)
+		if !strings.Contains(out, "| 配置项 | 环境变量 |") || !strings.Contains(out, "port最小只能为1,024") {
+			t.Errorf("Expected Chinese reference, got:\n%s", out)
+		}
+	})
+
+	t.Run("No Locale", func(t *testing.T) {
+		data, err := Markdown[DocsConfig]("docsapp", WithLocale(""))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !strings.Contains(string(data), "min=1024") {
+			t.Errorf("Expected raw rules without locale, got:\n%s", data)
+		}
+	})
+}
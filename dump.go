@@ -0,0 +1,162 @@
+package conf
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"go.yaml.in/yaml/v3"
+)
+
+var (
+	durationType      = reflect.TypeOf(time.Duration(0))
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// Dump 将已加载的配置渲染为 yaml、json 或 toml
+//
+// 键名与加载时一致 (mapstructure > yaml > json > toml > FieldName)，
+// 标记了 env:"strict"、secret:"true" 的字段以及 Secret[T] 会被替换为 "******"。
+// 不含敏感字段的配置导出后可以直接被 Load 重新加载。
+func Dump(cfg any, format string) ([]byte, error) {
+	val := reflect.ValueOf(cfg)
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return nil, fmt.Errorf("dump config: nil %T", cfg)
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("dump config: expected struct, got %s", val.Kind())
+	}
+
+	tree := dumpStruct(val)
+
+	switch strings.ToLower(format) {
+	case "yaml", "yml":
+		return yaml.Marshal(tree)
+	case "json":
+		data, err := json.MarshalIndent(tree, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case "toml":
+		return toml.Marshal(tree)
+	default:
+		return nil, fmt.Errorf("dump config: unsupported format %q", format)
+	}
+}
+
+// isSensitive 判断字段在导出时是否需要脱敏
+func isSensitive(field reflect.StructField) bool {
+	if isStrict(field) || field.Tag.Get("secret") == "true" {
+		return true
+	}
+	typ := field.Type
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return isSecretType(typ)
+}
+
+func dumpStruct(val reflect.Value) map[string]any {
+	out := make(map[string]any)
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if isSquash(field) {
+			if v, ok := dumpValue(val.Field(i)); ok {
+				m, _ := v.(map[string]any)
+				for name, sub := range m {
+					out[name] = sub
+				}
+			}
+			continue
+		}
+
+		name := resolveKeyName(field)
+		if name == "" {
+			continue
+		}
+
+		if isSensitive(field) {
+			out[name] = secretMask
+			continue
+		}
+
+		if v, ok := dumpValue(val.Field(i)); ok {
+			out[name] = v
+		}
+	}
+	return out
+}
+
+// dumpValue 将值转换为编码器友好的形式，nil 指针、nil 切片和 nil map 被省略
+func dumpValue(val reflect.Value) (any, bool) {
+	switch val.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if val.IsNil() {
+			return nil, false
+		}
+		return dumpValue(val.Elem())
+	}
+
+	typ := val.Type()
+	switch {
+	case typ == durationType:
+		// 与 Viper 的 StringToTimeDurationHookFunc 对应，便于重新加载
+		return val.Interface().(time.Duration).String(), true
+	case typ == timeType:
+		return val.Interface(), true
+	case isSecretType(typ):
+		return secretMask, true
+	case typ.Implements(textMarshalerType) && typ.Kind() == reflect.Struct:
+		text, err := val.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, false
+		}
+		return string(text), true
+	}
+
+	switch val.Kind() {
+	case reflect.Struct:
+		return dumpStruct(val), true
+
+	case reflect.Slice, reflect.Array:
+		if val.Kind() == reflect.Slice && val.IsNil() {
+			return nil, false
+		}
+		items := make([]any, 0, val.Len())
+		for i := 0; i < val.Len(); i++ {
+			if v, ok := dumpValue(val.Index(i)); ok {
+				items = append(items, v)
+			}
+		}
+		return items, true
+
+	case reflect.Map:
+		if val.IsNil() {
+			return nil, false
+		}
+		m := make(map[string]any, val.Len())
+		iter := val.MapRange()
+		for iter.Next() {
+			if v, ok := dumpValue(iter.Value()); ok {
+				m[fmt.Sprint(iter.Key().Interface())] = v
+			}
+		}
+		return m, true
+
+	default:
+		return val.Interface(), true
+	}
+}
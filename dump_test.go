@@ -0,0 +1,115 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type DumpSub struct {
+	Hosts   []string      `mapstructure:"hosts"`
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+type DumpConfig struct {
+	Name   string            `yaml:"name"`
+	Port   int               `json:"port"`
+	Ratio  float64           `mapstructure:"ratio"`
+	Labels map[string]string `mapstructure:"labels"`
+	Sub    *DumpSub          `mapstructure:"sub"`
+	Token  string            `mapstructure:"token" secret:"true"`
+}
+
+func TestDump_RoundTrip(t *testing.T) {
+	cfg := &DumpConfig{
+		Name:   "svc",
+		Port:   8080,
+		Ratio:  0.5,
+		Labels: map[string]string{"team": "infra"},
+		Sub:    &DumpSub{Hosts: []string{"a", "b"}, Timeout: 3 * time.Second},
+	}
+
+	for _, format := range []string{"yaml", "json", "toml"} {
+		t.Run(format, func(t *testing.T) {
+			data, err := Dump(cfg, format)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "config."+format), data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			loaded, err := Load[DumpConfig]("dumpapp", WithSearchPaths(dir), WithFileType(format))
+			if err != nil {
+				t.Fatalf("Expected dump to be loadable, got %v\n%s", err, data)
+			}
+
+			// token 被脱敏为占位文本，不参与比较
+			loaded.Token = ""
+			if !reflect.DeepEqual(cfg, loaded) {
+				t.Errorf("Round trip mismatch:\nwant %+v\ngot  %+v", cfg, loaded)
+			}
+		})
+	}
+}
+
+func TestDump_Redaction(t *testing.T) {
+	cfg := &struct {
+		Database Database       `mapstructure:"database"`
+		Token    string         `mapstructure:"token" secret:"true"`
+		Key      Secret[string] `mapstructure:"key"`
+	}{
+		Database: Database{Host: "db", Password: "p@ss"},
+		Token:    "tok-123",
+		Key:      NewSecret("key-456"),
+	}
+
+	data, err := Dump(cfg, "yaml")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	out := string(data)
+	for _, leaked := range []string{"p@ss", "tok-123", "key-456"} {
+		if strings.Contains(out, leaked) {
+			t.Errorf("Dump leaked %q:\n%s", leaked, out)
+		}
+	}
+	if !strings.Contains(out, "host: db") {
+		t.Errorf("Expected non-secret fields to be kept, got:\n%s", out)
+	}
+}
+
+func TestDump_SquashedEmbed(t *testing.T) {
+	type Base struct {
+		Host string `mapstructure:"host"`
+	}
+	type SquashConfig struct {
+		Base `mapstructure:",squash"`
+		Port int `mapstructure:"port"`
+	}
+	cfg := &SquashConfig{Base: Base{Host: "h"}, Port: 8080}
+
+	data, err := Dump(cfg, "yaml")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// squash 嵌入的字段位于外层，与加载时一致
+	if want := "host: h\nport: 8080\n"; string(data) != want {
+		t.Errorf("Expected %q, got %q", want, data)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load[SquashConfig]("dumpapp", WithSearchPaths(dir))
+	if err != nil || !reflect.DeepEqual(cfg, loaded) {
+		t.Errorf("Expected dump to round trip, got %+v, %v", loaded, err)
+	}
+}
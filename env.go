@@ -0,0 +1,124 @@
+package conf
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// envNaming 描述环境变量的命名规则
+type envNaming struct {
+	prefix string // 变量名前缀，为空时不加前缀
+	sep    string // 前缀与各级键名之间的分隔符
+}
+
+// key 根据前缀和键路径推导环境变量名
+// 规则: prefix="myapp", sep="_", path=["db", "host"] -> "MYAPP_DB_HOST"
+func (n envNaming) key(path []string) string {
+	key := strings.Join(path, n.sep)
+	if n.prefix != "" {
+		key = n.prefix + n.sep + key
+	}
+	return strings.ToUpper(key)
+}
+
+// envTag 是解析后的 env 标签
+//
+// 语法: env:"NAME1,NAME2,strict"
+//   - 除 strict 外的每一项都是显式声明的环境变量名，按顺序取第一个非空值
+//   - 未声明变量名时使用 envNaming 推导出的名称
+//   - strict 表示生产环境必须来自环境变量
+type envTag struct {
+	names  []string
+	strict bool
+}
+
+func parseEnvTag(field reflect.StructField) envTag {
+	var tag envTag
+	for _, part := range strings.Split(field.Tag.Get("env"), ",") {
+		switch part = strings.TrimSpace(part); part {
+		case "":
+		case "strict":
+			tag.strict = true
+		default:
+			tag.names = append(tag.names, part)
+		}
+	}
+	return tag
+}
+
+// names 返回叶子配置项对应的环境变量名 (按优先级)
+func (n envNaming) names(leaf leafField) []string {
+	if names := parseEnvTag(leaf.field).names; len(names) > 0 {
+		return names
+	}
+	return []string{n.key(leaf.path)}
+}
+
+// fileEnvSuffix 是 Docker/Kubernetes 密钥文件约定的后缀:
+// MYAPP_DB_PASSWORD_FILE=/run/secrets/db_password
+const fileEnvSuffix = "_FILE"
+
+// lookupEnv 按优先级查找候选变量，直接设置的变量优先于同名 _FILE 变量
+// 返回值来源的变量名 (可能带 _FILE 后缀)，均未设置时返回 ""
+func lookupEnv(names []string) (value, from string, err error) {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value, name, nil
+		}
+		if path := os.Getenv(name + fileEnvSuffix); path != "" {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return "", "", fmt.Errorf("read %s%s: %w", name, fileEnvSuffix, err)
+			}
+			return strings.TrimRight(string(data), "\r\n"), name + fileEnvSuffix, nil
+		}
+	}
+	return "", "", nil
+}
+
+// resolveEnvs 按 envNaming 读取每个叶子配置项的环境变量 (含 <NAME>_FILE)，写入 Viper 并记录来源
+//
+// 只读取 names 给出的变量 (声明的变量名或按前缀推导的名称)；逐项写入也让既没有出现在配置文件里、
+// 也没有默认值的字段能读到环境变量，纯 Env 运行对嵌套结构体和指针同样可靠。
+// v.Set 的优先级高于一切来源，因此命令行显式指定的配置项会被跳过
+func (s *loadState) resolveEnvs() error {
+	for _, leaf := range collectLeaves(s.typ) {
+		if changedFlag(s.flags, leaf.key()) != nil {
+			continue
+		}
+		value, from, err := lookupEnv(s.naming.names(leaf))
+		if err != nil {
+			return err
+		}
+		if from != "" {
+			s.v.Set(leaf.key(), value)
+			s.record(leaf.key(), Source{Kind: SourceEnv, Name: from, Raw: value})
+		}
+	}
+	return nil
+}
+
+// EnvVar 描述应用读取的一个环境变量
+type EnvVar struct {
+	Key    string   // 对应的配置项键路径，如 "db.password"
+	Names  []string // 候选变量名 (按优先级)，每个变量还可通过 <NAME>_FILE 提供
+	Strict bool     // 是否标记了 env:"strict"
+}
+
+// EnvVars 按结构体声明顺序列出 T 的每个配置项对应的环境变量
+// 命名规则与 Load 一致，受 WithEnvPrefix、WithEnvKeySeparator 影响
+func EnvVars[T any](appName string, opts ...Option) []EnvVar {
+	naming := newOptions(opts).envNaming(appName)
+
+	var vars []EnvVar
+	for _, leaf := range collectLeaves(reflect.TypeOf((*T)(nil)).Elem()) {
+		vars = append(vars, EnvVar{
+			Key:    leaf.key(),
+			Names:  naming.names(leaf),
+			Strict: isStrict(leaf.field),
+		})
+	}
+	return vars
+}
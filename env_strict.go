@@ -1,21 +1,34 @@
 package conf
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"reflect"
 	"strings"
+
+	"github.com/spf13/pflag"
 )
 
-// checkEnvStrict 检查标记了 env:"strict" 的字段在生产环境是否真的来自环境变量
-func checkEnvStrict(appName string, cfg interface{}) error {
+// currentEnv 返回当前运行环境 (GO_ENV 优先，其次 APP_ENV)，统一小写
+func currentEnv() string {
 	env := os.Getenv("GO_ENV")
 	if env == "" {
 		env = os.Getenv("APP_ENV")
 	}
-	env = strings.ToLower(env)
+	return strings.ToLower(env)
+}
 
-	if env != "production" && env != "prod" {
+// isProduction 判断是否运行在生产环境
+func isProduction() bool {
+	env := currentEnv()
+	return env == "production" || env == "prod"
+}
+
+// checkEnvStrict 检查标记了 env:"strict" 的字段在生产环境是否真的来自环境变量
+// sources 为加载时记录的来源，以实际生效的值为准
+func checkEnvStrict(naming envNaming, sources map[string]Source, cfg interface{}) error {
+	if !isProduction() {
 		return nil
 	}
 
@@ -24,7 +37,12 @@ func checkEnvStrict(appName string, cfg interface{}) error {
 		val = val.Elem()
 	}
 
-	return recursiveEnvCheck(appName, val)
+	return recursiveEnvCheck(naming, sources, val)
+}
+
+// isStrict 判断字段是否标记了 env:"strict" (可与显式变量名组合，如 env:"DATABASE_URL,strict")
+func isStrict(field reflect.StructField) bool {
+	return parseEnvTag(field).strict
 }
 
 // resolveKeyName 根据优先级获取字段名称
@@ -78,66 +96,43 @@ func resolveKeyName(field reflect.StructField) string {
 	return field.Name
 }
 
-func recursiveEnvCheck(prefix string, val reflect.Value) error {
-	// 处理指针：解引用，如果是 nil 则跳过
-	if val.Kind() == reflect.Ptr {
-		if val.IsNil() {
-			return nil
-		}
-		val = val.Elem()
-	}
-
-	if val.Kind() != reflect.Struct {
-		return nil
-	}
-
-	typ := val.Type()
-	for i := 0; i < val.NumField(); i++ {
-		field := typ.Field(i)
-		fieldVal := val.Field(i)
-
-		// 0. 跳过未导出字段 (Private fields)
-		if !field.IsExported() {
+func recursiveEnvCheck(naming envNaming, sources map[string]Source, val reflect.Value) error {
+	// 逐个检查叶子配置项，嵌套指针为 nil 时其下字段被跳过；收集全部缺失的变量后一并返回
+	var errs []error
+	for _, leaf := range collectLeaves(val.Type()) {
+		if !isStrict(leaf.field) {
 			continue
 		}
-
-		// 1. 获取统一的 Key Name
-		mapKey := resolveKeyName(field)
-
-		// 如果 resolveKeyName 返回空字符串，说明被显式忽略 ("-")
-		if mapKey == "" {
+		if _, ok := fieldByIndex(val, leaf.index); !ok {
 			continue
 		}
 
-		// 2. 拼接 Key
-		var currentKey string
-		if prefix != "" {
-			currentKey = strings.ToUpper(prefix + "_" + mapKey)
-		} else {
-			currentKey = strings.ToUpper(mapKey)
+		// 生效值必须来自候选环境变量 (或其 <NAME>_FILE 密钥文件)；命令行参数由 checkStrictFlags 单独报告
+		switch sources[leaf.key()].Kind {
+		case SourceEnv, SourceFlag:
+			continue
 		}
+		names := naming.names(leaf)
+		errs = append(errs, fmt.Errorf("security check failed: field '%s' (tag: '%s') must be set via environment variable '%s' in production", leaf.field.Name, leaf.path[len(leaf.path)-1], strings.Join(names, "' or '")))
+	}
+	return errors.Join(errs...)
+}
 
-		// 3. 递归处理嵌套结构体 (包含 Struct 和 *Struct)
-		derefType := fieldVal.Type()
-		if derefType.Kind() == reflect.Ptr {
-			derefType = derefType.Elem()
-		}
+// checkStrictFlags 禁止生产环境通过命令行参数设置 env:"strict" 字段
+// 命令行参数会出现在 ps 输出和进程审计日志中，不适合传递密钥
+func checkStrictFlags(fs *pflag.FlagSet, cfg interface{}) error {
+	if fs == nil || !isProduction() {
+		return nil
+	}
 
-		if derefType.Kind() == reflect.Struct {
-			// 递归传递
-			if err := recursiveEnvCheck(currentKey, fieldVal); err != nil {
-				return err
-			}
+	var errs []error
+	for _, leaf := range collectLeaves(reflect.TypeOf(cfg)) {
+		if !isStrict(leaf.field) {
 			continue
 		}
-
-		// 4. 检查 env:"strict" 标签
-		if tag := field.Tag.Get("env"); tag == "strict" {
-			// 必须检查环境变量是否非空
-			if os.Getenv(currentKey) == "" {
-				return fmt.Errorf("security check failed: field '%s' (tag: '%s') must be set via environment variable '%s' in production", field.Name, mapKey, currentKey)
-			}
+		if flag := changedFlag(fs, leaf.key()); flag != nil {
+			errs = append(errs, fmt.Errorf("security check failed: field '%s' (tag: '%s') must not be set via flag '--%s' in production", leaf.field.Name, leaf.path[len(leaf.path)-1], flag.Name))
 		}
 	}
-	return nil
+	return errors.Join(errs...)
 }
@@ -0,0 +1,159 @@
+package conf
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/oy3o/conf/validator"
+)
+
+// Stage 表示加载流程中产生错误的阶段
+type Stage string
+
+const (
+	StageUnknownKeys Stage = "unknown_keys" // 配置文件中存在未知的键
+	StageDecode      Stage = "decode"       // 值无法解码为字段类型
+	StageEnvStrict   Stage = "env_strict"   // 生产环境来源检查
+	StageValidate    Stage = "validate"     // 数据内容验证
+)
+
+// StageError 是某个阶段产生的错误
+type StageError struct {
+	Stage Stage
+	Err   error
+}
+
+func (e *StageError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Stage, e.Err)
+}
+
+func (e *StageError) Unwrap() error {
+	return e.Err
+}
+
+// LoadError 聚合加载过程中各阶段的全部错误 (WithAllErrors)
+// 实现了 Unwrap() []error，可直接用 errors.As 取出 *validator.ValidationError 等具体错误
+type LoadError struct {
+	Errors []*StageError // 按阶段顺序排列
+}
+
+func (e *LoadError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (e *LoadError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Stage 返回指定阶段的全部错误
+func (e *LoadError) Stage(stage Stage) []error {
+	var errs []error
+	for _, err := range e.Errors {
+		if err.Stage == stage {
+			errs = append(errs, err.Err)
+		}
+	}
+	return errs
+}
+
+// errorCollector 在 WithAllErrors 模式下收集各阶段的错误
+type errorCollector struct {
+	all  bool
+	errs []*StageError
+}
+
+// add 记录 stage 的错误；非聚合模式下原样返回该错误，调用方应立即终止
+func (c *errorCollector) add(stage Stage, err error) error {
+	if !c.all {
+		return err
+	}
+	c.errs = append(c.errs, &StageError{Stage: stage, Err: err})
+	return nil
+}
+
+// err 返回聚合后的错误，没有错误时返回 nil
+func (c *errorCollector) err() error {
+	if len(c.errs) == 0 {
+		return nil
+	}
+	return &LoadError{Errors: c.errs}
+}
+
+// DecodeError 表示某个配置项的值无法解码为字段类型 (如 port: "abc")
+type DecodeError struct {
+	Key string // 点分隔的键路径，列表元素为 "key[i]"
+	Err error  // 底层的 mapstructure 错误
+
+	// 值所在的配置文件位置，值来自环境变量、命令行参数或无法定位时为空
+	File   string
+	Line   int
+	Column int
+}
+
+func (e *DecodeError) Error() string {
+	if e.File == "" {
+		return fmt.Sprintf("'%s' %v", e.Key, e.Err)
+	}
+	return fmt.Sprintf("%s:%d:%d: '%s' %v", e.File, e.Line, e.Column, e.Key, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// dropDecodedFields 从验证错误中去掉解码失败的字段，全部去掉时返回 nil
+func dropDecodedFields(err, decodeErr error) error {
+	var verr *validator.ValidationError
+	if decodeErr == nil || !errors.As(err, &verr) {
+		return err
+	}
+
+	var keys []string
+	var walk func(err error)
+	walk = func(err error) {
+		switch e := err.(type) {
+		case *DecodeError:
+			keys = append(keys, strings.ToLower(e.Key))
+		case interface{ Unwrap() []error }:
+			for _, e := range e.Unwrap() {
+				walk(e)
+			}
+		}
+	}
+	walk(decodeErr)
+
+	failed := func(path string) bool {
+		path = strings.ToLower(path)
+		for _, k := range keys {
+			if path == k || strings.HasPrefix(path, k+".") || strings.HasPrefix(path, k+"[") {
+				return true
+			}
+		}
+		return false
+	}
+
+	kept := &validator.ValidationError{Errors: make(map[string]string)}
+	for path, msg := range verr.Errors {
+		if !failed(path) {
+			kept.Errors[path] = msg
+		}
+	}
+	for _, f := range verr.Fields {
+		if !failed(f.Path) {
+			kept.Fields = append(kept.Fields, f)
+		}
+	}
+	if len(kept.Errors) == 0 {
+		return nil
+	}
+	return kept
+}
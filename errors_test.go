@@ -0,0 +1,83 @@
+package conf
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/oy3o/conf/validator"
+)
+
+type AllErrorsConfig struct {
+	Name   string `mapstructure:"name" validate:"required"`
+	Port   int    `mapstructure:"port" validate:"min=1024"`
+	Limit  int    `mapstructure:"limit" validate:"min=1"`
+	Token  string `mapstructure:"token" env:"strict"`
+	APIKey string `mapstructure:"api_key" env:"strict"`
+}
+
+func TestLoad_AllErrors(t *testing.T) {
+	configDir := writeFiles(t, map[string]string{
+		"config.yaml": "nmae: svc\nport: 80\nlimit: many\n",
+	})
+
+	os.Setenv("GO_ENV", "production")
+	defer os.Unsetenv("GO_ENV")
+
+	opts := []Option{WithSearchPaths(configDir), WithProfiles(), WithLocale("en")}
+
+	t.Run("First Stage Only", func(t *testing.T) {
+		_, err := Load[AllErrorsConfig]("allerrapp", opts...)
+		var uerr *UnknownKeyError
+		if !errors.As(err, &uerr) {
+			t.Fatalf("Expected UnknownKeyError, got %v", err)
+		}
+		var lerr *LoadError
+		if errors.As(err, &lerr) {
+			t.Error("Expected no LoadError without WithAllErrors")
+		}
+	})
+
+	t.Run("Aggregated", func(t *testing.T) {
+		_, err := Load[AllErrorsConfig]("allerrapp", append(opts, WithAllErrors())...)
+
+		var lerr *LoadError
+		if !errors.As(err, &lerr) {
+			t.Fatalf("Expected LoadError, got %v", err)
+		}
+		for _, stage := range []Stage{StageUnknownKeys, StageDecode, StageEnvStrict, StageValidate} {
+			if len(lerr.Stage(stage)) == 0 {
+				t.Errorf("Expected errors for stage %s, got: %v", stage, err)
+			}
+		}
+
+		// 两个 strict 字段都应被报告
+		msg := err.Error()
+		if !strings.Contains(msg, "ALLERRAPP_TOKEN") || !strings.Contains(msg, "ALLERRAPP_API_KEY") {
+			t.Errorf("Expected every missing strict variable, got: %s", msg)
+		}
+
+		// 具体错误类型可通过 errors.As 取出
+		var uerr *UnknownKeyError
+		var derr *DecodeError
+		var verr *validator.ValidationError
+		if !errors.As(err, &uerr) || !errors.As(err, &derr) || !errors.As(err, &verr) {
+			t.Fatalf("Expected errors.As to reach every stage error, got: %s", msg)
+		}
+		if derr.Key != "limit" {
+			t.Errorf("Expected decode error for limit, got %+v", derr)
+		}
+
+		// 解码失败的 limit 不再重复报告验证错误
+		if _, ok := verr.Errors["limit"]; ok {
+			t.Errorf("Expected no validation error for undecodable field, got %v", verr.Errors)
+		}
+		if _, ok := verr.Errors["name"]; !ok {
+			t.Errorf("Expected validation error for name, got %v", verr.Errors)
+		}
+		if _, ok := verr.Errors["port"]; !ok {
+			t.Errorf("Expected validation error for port, got %v", verr.Errors)
+		}
+	})
+}
@@ -0,0 +1,298 @@
+package conf
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// exampleEntry 是示例配置中的一个配置项或配置段
+type exampleEntry struct {
+	key      string
+	comments []string
+	value    any            // 叶子配置项的示例值
+	children []exampleEntry // 非空表示这是一个嵌套配置段
+	disabled bool           // 以注释形式输出 (env:"strict" 字段等)
+}
+
+// Example 根据 T 生成带注释的示例配置文件 (yaml、json 或 toml)
+//
+// 每个配置项取 default 标签作为示例值，注释由 desc 标签和 validate 规则组成；
+// env:"strict" 字段以注释形式给出，提示其必须通过环境变量提供。
+// json 不支持注释，因此只包含可写入文件的配置项。
+func Example[T any](format string) ([]byte, error) {
+	entries := exampleEntries(reflect.TypeOf((*T)(nil)).Elem(), make(map[reflect.Type]bool))
+
+	var buf bytes.Buffer
+	switch strings.ToLower(format) {
+	case "yaml", "yml":
+		writeYAMLExample(&buf, entries, 0)
+	case "json":
+		writeJSONExample(&buf, entries, 0)
+		buf.WriteByte('\n')
+	case "toml":
+		writeTOMLExample(&buf, entries, nil)
+	default:
+		return nil, fmt.Errorf("example config: unsupported format %q", format)
+	}
+	return buf.Bytes(), nil
+}
+
+// EnvTemplate 根据 T 生成 .env.example，每个配置项一行，命名规则与 Load 一致
+func EnvTemplate[T any](appName string, opts ...Option) []byte {
+	naming := newOptions(opts).envNaming(appName)
+
+	var buf bytes.Buffer
+	for _, leaf := range collectLeaves(reflect.TypeOf((*T)(nil)).Elem()) {
+		comments := fieldComments(leaf.field)
+		fmt.Fprintf(&buf, "# %s", leaf.key())
+		if len(comments) > 0 {
+			fmt.Fprintf(&buf, ": %s", strings.Join(comments, "; "))
+		}
+		buf.WriteByte('\n')
+
+		value := ""
+		if !isSensitive(leaf.field) {
+			value = leaf.field.Tag.Get("default")
+		}
+		fmt.Fprintf(&buf, "%s=%s\n", naming.names(leaf)[0], value)
+	}
+	return buf.Bytes()
+}
+
+// fieldComments 由 desc 标签、validate 规则和 strict 说明组成注释
+func fieldComments(field reflect.StructField) []string {
+	var comments []string
+	if desc := field.Tag.Get("desc"); desc != "" {
+		comments = append(comments, desc)
+	}
+	if rules := field.Tag.Get("validate"); rules != "" {
+		comments = append(comments, "validate: "+rules)
+	}
+	if tag := parseEnvTag(field); tag.strict {
+		hint := "must be set via environment variable in production"
+		if len(tag.names) > 0 {
+			hint = fmt.Sprintf("must be set via %s in production", strings.Join(tag.names, " or "))
+		}
+		comments = append(comments, hint)
+	}
+	return comments
+}
+
+// exampleEntries 按声明顺序生成配置项，visiting 与 walkLeaves 相同，引用自身 (或外层) 类型的字段作为一个叶子配置项
+func exampleEntries(typ reflect.Type, visiting map[reflect.Type]bool) []exampleEntry {
+	typ = derefType(typ)
+	visiting[typ] = true
+	defer delete(visiting, typ)
+
+	var entries []exampleEntry
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if isSquash(field) && !visiting[derefType(field.Type)] {
+			entries = append(entries, exampleEntries(field.Type, visiting)...)
+			continue
+		}
+		name := resolveKeyName(field)
+		if name == "" {
+			continue
+		}
+
+		entry := exampleEntry{key: name, comments: fieldComments(field)}
+		if isNestedStruct(field.Type) && !visiting[derefType(field.Type)] {
+			entry.children = exampleEntries(field.Type, visiting)
+			if len(entry.children) == 0 {
+				continue
+			}
+		} else {
+			entry.value, entry.disabled = exampleValue(field)
+			if isStrict(field) {
+				entry.disabled = true
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// exampleValue 返回字段的示例值，无法给出可加载的示例值时返回 disabled
+func exampleValue(field reflect.StructField) (any, bool) {
+	if def, ok := field.Tag.Lookup("default"); ok {
+		if v, ok := parseDefault(field.Type, def); ok {
+			return v, false
+		}
+	}
+
+	typ := field.Type
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if isSecretType(typ) {
+		typ = reflect.New(typ).Interface().(secretSetter).innerType()
+	}
+
+	switch {
+	case typ == durationType:
+		return "0s", false
+	case typ == timeType:
+		return "2006-01-02T15:04:05Z", true
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return false, false
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return 0, false
+	case reflect.Float32, reflect.Float64:
+		return 0.0, false
+	case reflect.Slice, reflect.Array:
+		return []any{}, false
+	case reflect.Map:
+		return map[string]any{}, false
+	case reflect.String:
+		return "", false
+	default:
+		return "", true
+	}
+}
+
+func writeComments(buf *bytes.Buffer, indent string, comments []string) {
+	for _, c := range comments {
+		fmt.Fprintf(buf, "%s# %s\n", indent, c)
+	}
+}
+
+// writeYAMLExample 以块格式输出配置段，值使用 JSON 字面量 (同时也是合法的 YAML 流格式)
+func writeYAMLExample(buf *bytes.Buffer, entries []exampleEntry, depth int) {
+	indent := strings.Repeat("  ", depth)
+	for _, e := range entries {
+		writeComments(buf, indent, e.comments)
+		if e.children != nil {
+			fmt.Fprintf(buf, "%s%s:\n", indent, e.key)
+			writeYAMLExample(buf, e.children, depth+1)
+			continue
+		}
+
+		prefix := ""
+		if e.disabled {
+			prefix = "# "
+		}
+		fmt.Fprintf(buf, "%s%s%s: %s\n", indent, prefix, e.key, jsonLiteral(e.value))
+	}
+}
+
+func writeJSONExample(buf *bytes.Buffer, entries []exampleEntry, depth int) {
+	indent := strings.Repeat("  ", depth+1)
+	buf.WriteString("{")
+
+	first := true
+	for _, e := range entries {
+		if e.disabled {
+			continue
+		}
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+
+		fmt.Fprintf(buf, "\n%s%s: ", indent, jsonLiteral(e.key))
+		if e.children != nil {
+			writeJSONExample(buf, e.children, depth+1)
+		} else {
+			buf.WriteString(jsonLiteral(e.value))
+		}
+	}
+
+	if !first {
+		buf.WriteString("\n" + strings.Repeat("  ", depth))
+	}
+	buf.WriteString("}")
+}
+
+// writeTOMLExample 先输出当前表的键值，再输出子表 (TOML 要求键值位于子表之前)
+func writeTOMLExample(buf *bytes.Buffer, entries []exampleEntry, table []string) {
+	for _, e := range entries {
+		if e.children != nil {
+			continue
+		}
+		writeComments(buf, "", e.comments)
+		prefix := ""
+		if e.disabled {
+			prefix = "# "
+		}
+		fmt.Fprintf(buf, "%s%s = %s\n", prefix, tomlKey(e.key), tomlLiteral(e.value))
+	}
+
+	for _, e := range entries {
+		if e.children == nil {
+			continue
+		}
+		sub := append(append([]string(nil), table...), tomlKey(e.key))
+		if buf.Len() > 0 {
+			buf.WriteByte('\n')
+		}
+		writeComments(buf, "", e.comments)
+		fmt.Fprintf(buf, "[%s]\n", strings.Join(sub, "."))
+		writeTOMLExample(buf, e.children, sub)
+	}
+}
+
+func jsonLiteral(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return `""`
+	}
+	return string(data)
+}
+
+func tomlKey(key string) string {
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return strconv.Quote(key)
+		}
+	}
+	return key
+}
+
+// tomlLiteral 输出 TOML 字面量，map 使用行内表
+func tomlLiteral(v any) string {
+	switch v := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		parts := make([]string, 0, len(keys))
+		for _, k := range keys {
+			parts = append(parts, tomlKey(k)+" = "+tomlLiteral(v[k]))
+		}
+		if len(parts) == 0 {
+			return "{}"
+		}
+		return "{ " + strings.Join(parts, ", ") + " }"
+	case []any:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, tomlLiteral(item))
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case float64:
+		// 保留小数点，避免被 TOML 解析为整数
+		s := strconv.FormatFloat(v, 'f', -1, 64)
+		if !strings.ContainsAny(s, ".eE") {
+			s += ".0"
+		}
+		return s
+	default:
+		return jsonLiteral(v)
+	}
+}
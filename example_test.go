@@ -0,0 +1,141 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type ExampleDB struct {
+	Host     string        `mapstructure:"host" validate:"required" default:"localhost" desc:"database host"`
+	Port     int           `mapstructure:"port" validate:"min=1024" default:"5432"`
+	Timeout  time.Duration `mapstructure:"timeout" default:"5s"`
+	Password string        `mapstructure:"password" env:"strict"`
+}
+
+type ExampleConfig struct {
+	Name  string            `mapstructure:"name" default:"svc" desc:"service name"`
+	Ratio float64           `mapstructure:"ratio"`
+	Tags  []string          `mapstructure:"tags"`
+	Meta  map[string]string `mapstructure:"meta"`
+	DB    ExampleDB         `mapstructure:"db"`
+}
+
+func TestExample_Loadable(t *testing.T) {
+	want := &ExampleConfig{
+		Name: "svc",
+		Tags: []string{},
+		Meta: map[string]string{},
+		DB:   ExampleDB{Host: "localhost", Port: 5432, Timeout: 5 * time.Second},
+	}
+
+	for _, format := range []string{"yaml", "json", "toml"} {
+		t.Run(format, func(t *testing.T) {
+			data, err := Example[ExampleConfig](format)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "config."+format), data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := Load[ExampleConfig]("exampleapp", WithSearchPaths(dir), WithFileType(format))
+			if err != nil {
+				t.Fatalf("Expected example to be loadable, got %v\n%s", err, data)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Unexpected values:\nwant %+v\ngot  %+v\n%s", want, got, data)
+			}
+		})
+	}
+}
+
+func TestExample_Comments(t *testing.T) {
+	data, err := Example[ExampleConfig]("yaml")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	out := string(data)
+	for _, want := range []string{
+		"# database host\n",
+		"# validate: required\n",
+		"  # password: \"\"\n",
+		"must be set via environment variable",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected example to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestExample_RecursiveType(t *testing.T) {
+	type Node struct {
+		Name  string `mapstructure:"name" default:"root"`
+		Child *Node  `mapstructure:"child"`
+	}
+
+	data, err := Example[Node]("yaml")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// 引用自身的字段作为一个叶子配置项输出，无法给出示例值而以注释形式给出
+	if want := "name: \"root\"\n# child: \"\"\n"; string(data) != want {
+		t.Errorf("Expected %q, got %q", want, data)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load[Node]("nodeapp", WithSearchPaths(dir))
+	if err != nil || got.Name != "root" || got.Child != nil {
+		t.Errorf("Expected example to be loadable, got %+v, %v", got, err)
+	}
+}
+
+func TestExample_SquashedEmbed(t *testing.T) {
+	type Base struct {
+		Host string `mapstructure:"host" default:"localhost"`
+	}
+	type SquashConfig struct {
+		Base `mapstructure:",squash"`
+		Port int `mapstructure:"port" default:"80"`
+	}
+
+	data, err := Example[SquashConfig]("yaml")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// squash 嵌入的字段位于外层，与加载时一致
+	if want := "host: \"localhost\"\nport: 80\n"; string(data) != want {
+		t.Errorf("Expected %q, got %q", want, data)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load[SquashConfig]("exampleapp", WithSearchPaths(dir)); err != nil {
+		t.Errorf("Expected example to be loadable, got %v\n%s", err, data)
+	}
+}
+
+func TestEnvTemplate(t *testing.T) {
+	out := string(EnvTemplate[ExampleConfig]("exampleapp"))
+
+	for _, want := range []string{
+		"# name: service name\nEXAMPLEAPP_NAME=svc\n",
+		"EXAMPLEAPP_DB_PORT=5432\n",
+		"EXAMPLEAPP_DB_PASSWORD=\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected template to contain %q, got:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package conf
+
+import (
+	"encoding"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// leafField 描述配置结构体中的一个叶子配置项
+type leafField struct {
+	path  []string            // 按 resolveKeyName 解析出的键路径
+	index []int               // 从根结构体出发的字段下标链 (跨越指针)
+	field reflect.StructField // 叶子字段本身
+}
+
+// key 返回点分隔的键名，如 "db.host"
+func (f leafField) key() string {
+	return strings.Join(f.path, ".")
+}
+
+// collectLeaves 按结构体声明顺序收集 typ 中所有叶子配置项
+// 嵌套结构体 (包含 *Struct) 会被展开，squash 嵌入的结构体与 mapstructure 一样并入外层路径，
+// 未导出字段和显式忽略 ("-") 的字段会被跳过；
+// 引用自身 (或外层) 类型的字段 (如 Child *Node) 不再展开，整体作为一个叶子配置项
+func collectLeaves(typ reflect.Type) []leafField {
+	var leaves []leafField
+	walkLeaves(typ, nil, nil, make(map[reflect.Type]bool), &leaves)
+	return leaves
+}
+
+// walkLeaves 展开 typ 的字段，visiting 记录当前路径上正在展开的结构体类型
+func walkLeaves(typ reflect.Type, path []string, index []int, visiting map[reflect.Type]bool, leaves *[]leafField) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return
+	}
+	visiting[typ] = true
+	defer delete(visiting, typ)
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if isSquash(field) && !visiting[derefType(field.Type)] {
+			walkLeaves(field.Type, path, append(append([]int(nil), index...), i), visiting, leaves)
+			continue
+		}
+
+		name := resolveKeyName(field)
+		if name == "" {
+			continue
+		}
+
+		// 复制切片，避免兄弟字段共享底层数组
+		fieldPath := append(append([]string(nil), path...), name)
+		fieldIndex := append(append([]int(nil), index...), i)
+
+		if isNestedStruct(field.Type) && !visiting[derefType(field.Type)] {
+			walkLeaves(field.Type, fieldPath, fieldIndex, visiting, leaves)
+			continue
+		}
+
+		*leaves = append(*leaves, leafField{path: fieldPath, index: fieldIndex, field: field})
+	}
+}
+
+// isNestedStruct 判断类型是否需要作为嵌套配置段展开
+// time.Time、Secret[T] 以及实现了 encoding.TextUnmarshaler 的结构体被视为单个值
+func isNestedStruct(typ reflect.Type) bool {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return false
+	}
+	if typ == timeType || isSecretType(typ) || reflect.PointerTo(typ).Implements(textUnmarshalerType) {
+		return false
+	}
+	return true
+}
+
+// isSquash 判断字段是否以 ",squash" 嵌入，其字段应并入外层配置段
+// 与 mapstructure 一致，只看 mapstructure > yaml > json > toml 中第一个非空的标签
+func isSquash(field reflect.StructField) bool {
+	if derefType(field.Type).Kind() != reflect.Struct {
+		return false
+	}
+	for _, name := range []string{"mapstructure", "yaml", "json", "toml"} {
+		if tag := field.Tag.Get(name); tag != "" {
+			parts := strings.Split(tag, ",")
+			for _, opt := range parts[1:] {
+				if opt == "squash" {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return false
+}
+
+// derefType 剥离指针
+func derefType(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ
+}
+
+// fieldByIndex 按下标链取值，途经 nil 指针时返回 false
+func fieldByIndex(val reflect.Value, index []int) (reflect.Value, bool) {
+	for _, i := range index {
+		for val.Kind() == reflect.Ptr {
+			if val.IsNil() {
+				return reflect.Value{}, false
+			}
+			val = val.Elem()
+		}
+		val = val.Field(i)
+	}
+	return val, true
+}
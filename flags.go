@@ -0,0 +1,91 @@
+package conf
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// RegisterFlags 为 T 的每个叶子配置项注册一个命令行参数
+//
+// 参数名为点分隔的键路径 (如 --db.port)，默认值取自 default 标签，
+// 说明取自 usage 或 desc 标签。已存在的同名参数会被跳过，便于手动定制个别参数。
+// 注册后需将 fs 通过 WithFlags 传给 Load。
+func RegisterFlags[T any](fs *pflag.FlagSet) {
+	for _, leaf := range collectLeaves(reflect.TypeOf((*T)(nil)).Elem()) {
+		name := leaf.key()
+		if fs.Lookup(name) != nil {
+			continue
+		}
+
+		def := leaf.field.Tag.Get("default")
+		usage := leaf.field.Tag.Get("usage")
+		if usage == "" {
+			usage = leaf.field.Tag.Get("desc")
+		}
+
+		typ := leaf.field.Type
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+
+		switch {
+		case typ == durationType:
+			d, _ := time.ParseDuration(def)
+			fs.Duration(name, d, usage)
+		case typ.Kind() == reflect.Bool:
+			b, _ := strconv.ParseBool(def)
+			fs.Bool(name, b, usage)
+		case typ.Kind() >= reflect.Int && typ.Kind() <= reflect.Int64:
+			i, _ := strconv.ParseInt(def, 10, 64)
+			fs.Int64(name, i, usage)
+		case typ.Kind() >= reflect.Uint && typ.Kind() <= reflect.Uint64:
+			u, _ := strconv.ParseUint(def, 10, 64)
+			fs.Uint64(name, u, usage)
+		case typ.Kind() == reflect.Float32 || typ.Kind() == reflect.Float64:
+			f, _ := strconv.ParseFloat(def, 64)
+			fs.Float64(name, f, usage)
+		case typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.String:
+			var items []string
+			if def != "" {
+				items = strings.Split(def, ",")
+			}
+			fs.StringSlice(name, items, usage)
+		default:
+			fs.String(name, def, usage)
+		}
+	}
+}
+
+// changedFlag 返回命令行中显式指定的参数，未指定时返回 nil
+func changedFlag(fs *pflag.FlagSet, key string) *pflag.Flag {
+	if fs == nil {
+		return nil
+	}
+	if flag := fs.Lookup(key); flag != nil && flag.Changed {
+		return flag
+	}
+	return nil
+}
+
+// bindFlags 将命令行中显式指定的参数绑定到对应配置项，优先级高于环境变量和配置文件
+//
+// 只绑定 Changed 的参数：未指定的参数不参与合并，
+// 避免参数零值把嵌套指针、环境变量和配置文件中的值覆盖掉。
+func (s *loadState) bindFlags() error {
+	if s.flags == nil {
+		return nil
+	}
+	for _, leaf := range collectLeaves(s.typ) {
+		if flag := changedFlag(s.flags, leaf.key()); flag != nil {
+			if err := s.v.BindPFlag(leaf.key(), flag); err != nil {
+				return err
+			}
+			s.record(leaf.key(), Source{Kind: SourceFlag, Name: "--" + flag.Name, Raw: flag.Value.String()})
+		}
+	}
+	return nil
+}
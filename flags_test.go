@@ -0,0 +1,79 @@
+package conf
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+type FlagConfig struct {
+	Debug    bool     `mapstructure:"debug" usage:"enable debug mode"`
+	Database Database `mapstructure:"database"`
+	Tags     []string `mapstructure:"tags" desc:"instance tags"`
+}
+
+func newFlagSet(t *testing.T, args ...string) *pflag.FlagSet {
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	RegisterFlags[FlagConfig](fs)
+	if err := fs.Parse(args); err != nil {
+		t.Fatalf("Failed to parse flags: %v", err)
+	}
+	return fs
+}
+
+func TestRegisterFlags(t *testing.T) {
+	fs := newFlagSet(t)
+
+	port := fs.Lookup("database.port")
+	if port == nil || port.DefValue != "3306" {
+		t.Fatalf("Expected database.port flag with default 3306, got %+v", port)
+	}
+	if debug := fs.Lookup("debug"); debug == nil || debug.Usage != "enable debug mode" || debug.NoOptDefVal != "true" {
+		t.Errorf("Expected boolean debug flag with usage, got %+v", debug)
+	}
+	if tags := fs.Lookup("tags"); tags == nil || tags.Usage != "instance tags" {
+		t.Errorf("Expected tags flag with desc as usage, got %+v", tags)
+	}
+}
+
+func TestLoad_FlagsPrecedence(t *testing.T) {
+	configDir := createConfigFile(t, "config.yaml", "database:\n  host: file-host\n  port: 5432\n")
+
+	os.Setenv("FLAGAPP_DATABASE_HOST", "env-host")
+	defer os.Unsetenv("FLAGAPP_DATABASE_HOST")
+
+	fs := newFlagSet(t, "--database.host=flag-host", "--debug", "--tags=a,b")
+	cfg, report, err := LoadWithReport[FlagConfig]("flagapp", WithSearchPaths(configDir), WithFlags(fs))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if cfg.Database.Host != "flag-host" || !cfg.Debug || len(cfg.Tags) != 2 {
+		t.Errorf("Expected flag values to win, got %+v", cfg)
+	}
+	// 未指定的参数不应覆盖配置文件
+	if cfg.Database.Port != 5432 {
+		t.Errorf("Expected port from file, got %d", cfg.Database.Port)
+	}
+
+	if src, _ := report.Source("database.host"); src.Kind != SourceFlag || src.Name != "--database.host" {
+		t.Errorf("Expected flag provenance, got %+v", src)
+	}
+}
+
+func TestLoad_StrictRejectsFlag(t *testing.T) {
+	configDir := createConfigFile(t, "config.yaml", "database:\n  host: localhost\n")
+
+	os.Setenv("GO_ENV", "production")
+	defer os.Unsetenv("GO_ENV")
+	os.Setenv("FLAGAPP_DATABASE_PASSWORD", "from-env")
+	defer os.Unsetenv("FLAGAPP_DATABASE_PASSWORD")
+
+	fs := newFlagSet(t, "--database.password=leaked")
+	_, err := Load[FlagConfig]("flagapp", WithSearchPaths(configDir), WithFlags(fs))
+	if err == nil || !strings.Contains(err.Error(), "--database.password") {
+		t.Errorf("Expected strict field to be rejected when set by flag, got %v", err)
+	}
+}
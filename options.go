@@ -1,10 +1,30 @@
 package conf
 
+import (
+	"github.com/oy3o/conf/validator"
+	"github.com/spf13/pflag"
+)
+
 type options struct {
 	searchPaths []string
 	fileType    string
 	fileName    string
 	locale      string // zh, en, or ""
+
+	envPrefix    *string // nil 表示使用 appName
+	envSeparator string
+
+	profiles []string // nil 表示按运行环境推导
+
+	flags *pflag.FlagSet
+
+	configFile string // 显式指定的配置文件，优先于 searchPaths + fileName
+
+	allErrors bool // 聚合所有阶段的错误，而不是在第一个失败的阶段返回
+
+	deepValidation bool // 标签验证之外，递归调用嵌套值的 Validate() 方法
+
+	validator *validator.Validator // nil 表示每次按 locale 新建
 }
 
 type Option func(*options)
@@ -15,7 +35,18 @@ func defaultOptions() *options {
 		fileType:    "yaml",
 		fileName:    "config",
 		locale:      "zh", // 默认开启中文，对国内开发友好
+
+		envSeparator: "_",
+	}
+}
+
+// newOptions 在默认值基础上依次应用 Option
+func newOptions(opts []Option) *options {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
 	}
+	return o
 }
 
 // WithSearchPaths 指定配置文件的搜索路径
@@ -39,9 +70,91 @@ func WithFileName(n string) Option {
 	}
 }
 
-// WithLocale 指定验证错误语言 ("zh", "en", ""=关闭)
+// WithConfigFile 直接指定配置文件路径 (类型由扩展名推断)，文件不存在时 Load 返回错误
+// 指定后默认不再叠加 profile 文件，需要时可通过 WithProfiles 显式开启
+func WithConfigFile(path string) Option {
+	return func(o *options) {
+		o.configFile = path
+	}
+}
+
+// WithLocale 指定验证错误语言 ("zh", "en", "ja", "zh-TW" 等，""=关闭)
+// 未注册的语言会使 Load 返回 validator.ErrUnknownLocale；需要回退时可配合 WithValidator 使用 validator.New 的回退链
 func WithLocale(locale string) Option {
 	return func(o *options) {
 		o.locale = locale
 	}
 }
+
+// WithProfiles 指定叠加在基础配置文件之上的 profile 及其顺序
+// 例如 WithProfiles("production", "local") 会依次合并 config.production.yaml 和 config.local.yaml；
+// 不传参数表示只读取基础配置文件
+func WithProfiles(profiles ...string) Option {
+	return func(o *options) {
+		o.profiles = append([]string{}, profiles...)
+	}
+}
+
+// WithFlags 使用命令行参数覆盖配置 (优先级: flag > env > file > default)
+// 参数名为点分隔的键路径，如 --db.port=5433，可通过 RegisterFlags 自动注册；fs 需已完成 Parse
+func WithFlags(fs *pflag.FlagSet) Option {
+	return func(o *options) {
+		o.flags = fs
+	}
+}
+
+// WithEnvPrefix 指定环境变量前缀 (默认使用 appName，传入 "" 表示不加前缀)
+func WithEnvPrefix(prefix string) Option {
+	return func(o *options) {
+		o.envPrefix = &prefix
+	}
+}
+
+// WithEnvKeySeparator 指定环境变量中前缀与各级键名之间的分隔符 (默认 "_")
+func WithEnvKeySeparator(sep string) Option {
+	return func(o *options) {
+		o.envSeparator = sep
+	}
+}
+
+// WithAllErrors 让 Load 执行完解码、Env Strict 检查和验证的全部阶段，
+// 以 *LoadError 一次性返回所有问题 (适合 CI 检查配置)
+func WithAllErrors() Option {
+	return func(o *options) {
+		o.allErrors = true
+	}
+}
+
+// WithDeepValidation 对整棵配置树执行标签验证，并递归调用根值以及每个嵌套结构体、
+// 指针、切片元素和 map 值上的 Validate() 方法 (SelfValidatable 或 ContextValidatable)，错误按所在键路径归属
+// 默认模式下，根类型实现 SelfValidatable 时只调用其 Validate() 而跳过标签验证
+func WithDeepValidation() Option {
+	return func(o *options) {
+		o.deepValidation = true
+	}
+}
+
+// WithValidator 使用预先配置好的验证器 (如已通过 RegisterRule 注册了自定义规则)
+// 验证器可在多次 Load 之间共享；此时验证错误的语言由验证器自身决定，WithLocale 不再作用于验证
+func WithValidator(v *validator.Validator) Option {
+	return func(o *options) {
+		o.validator = v
+	}
+}
+
+// newValidator 返回 WithValidator 指定的验证器，未指定时按 locale 新建
+func (o *options) newValidator() (*validator.Validator, error) {
+	if o.validator != nil {
+		return o.validator, nil
+	}
+	return validator.New(o.locale)
+}
+
+// envNaming 返回绑定和 Env Strict 检查共用的命名规则
+func (o *options) envNaming(appName string) envNaming {
+	prefix := appName
+	if o.envPrefix != nil {
+		prefix = *o.envPrefix
+	}
+	return envNaming{prefix: prefix, sep: o.envSeparator}
+}
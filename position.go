@@ -0,0 +1,319 @@
+package conf
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/oy3o/conf/validator"
+	"github.com/pelletier/go-toml/v2/unstable"
+	"go.yaml.in/yaml/v3"
+)
+
+// position 是配置项在文件中的位置，行列号从 1 开始
+type position struct {
+	file         string
+	line, column int
+}
+
+// filePositions 按 configType (加载时解析该文件使用的格式) 解析配置文件，返回每个键的位置
+//
+// 键为小写的点分路径 (与 Viper 一致)，列表元素记为 "key[i]"。
+// 不支持的格式返回空索引。
+func filePositions(path, configType string) (map[string]position, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]position)
+	record := func(key string, line, column int) {
+		index[strings.ToLower(key)] = position{file: path, line: line, column: column}
+	}
+
+	switch strings.ToLower(configType) {
+	case "yaml", "yml":
+		err = yamlPositions(data, record)
+	case "json":
+		err = jsonPositions(data, record)
+	case "toml":
+		err = tomlPositions(data, record)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("locate keys in %s: %w", path, err)
+	}
+	return index, nil
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// parentKey 去掉键路径的最后一段，如 "db.port" -> "db"，"hosts[0]" -> "hosts"
+func parentKey(key string) string {
+	if i := strings.LastIndexAny(key, ".["); i >= 0 {
+		return key[:i]
+	}
+	return ""
+}
+
+func yamlPositions(data []byte, record func(key string, line, column int)) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	var walk func(node *yaml.Node, prefix string)
+	walk = func(node *yaml.Node, prefix string) {
+		switch node.Kind {
+		case yaml.DocumentNode:
+			for _, n := range node.Content {
+				walk(n, prefix)
+			}
+		case yaml.AliasNode:
+			walk(node.Alias, prefix)
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				k, v := node.Content[i], node.Content[i+1]
+				if k.Tag == "!!merge" {
+					walk(v, prefix)
+					continue
+				}
+				key := joinKey(prefix, k.Value)
+				record(key, k.Line, k.Column)
+				walk(v, key)
+			}
+		case yaml.SequenceNode:
+			for i, n := range node.Content {
+				key := prefix + "[" + strconv.Itoa(i) + "]"
+				record(key, n.Line, n.Column)
+				walk(n, key)
+			}
+		}
+	}
+	walk(&doc, "")
+	return nil
+}
+
+// jsonPositions 逐个读取 JSON token，用读取前的偏移量定位键和列表元素
+func jsonPositions(data []byte, record func(key string, line, column int)) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	// start 返回下一个 token 的起始偏移量 (跳过空白和分隔符)
+	start := func() int {
+		off := int(dec.InputOffset())
+		for off < len(data) && strings.IndexByte(" \t\r\n,:", data[off]) >= 0 {
+			off++
+		}
+		return off
+	}
+	at := func(key string, off int) {
+		lead := data[:off]
+		record(key, bytes.Count(lead, []byte{'\n'})+1, off-bytes.LastIndexByte(lead, '\n'))
+	}
+
+	var walk func(prefix string) error
+	walk = func(prefix string) error {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'):
+			for dec.More() {
+				off := start()
+				tok, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				key := joinKey(prefix, tok.(string))
+				at(key, off)
+				if err := walk(key); err != nil {
+					return err
+				}
+			}
+			_, err = dec.Token()
+		case json.Delim('['):
+			for i := 0; dec.More(); i++ {
+				key := prefix + "[" + strconv.Itoa(i) + "]"
+				at(key, start())
+				if err := walk(key); err != nil {
+					return err
+				}
+			}
+			_, err = dec.Token()
+		}
+		return err
+	}
+	return walk("")
+}
+
+func tomlPositions(data []byte, record func(key string, line, column int)) error {
+	p := &unstable.Parser{}
+	p.Reset(data)
+
+	// keyOf 拼接 (可能带点的) 键，并记录每一段的位置
+	keyOf := func(prefix string, node *unstable.Node) string {
+		it := node.Key()
+		for it.Next() {
+			part := it.Node()
+			prefix = joinKey(prefix, string(part.Data))
+			pos := p.Shape(part.Raw).Start
+			record(prefix, pos.Line, pos.Column)
+		}
+		return prefix
+	}
+
+	var walkValue func(prefix string, node *unstable.Node)
+	walkValue = func(prefix string, node *unstable.Node) {
+		if node.Kind != unstable.InlineTable {
+			return
+		}
+		it := node.Children()
+		for it.Next() {
+			kv := it.Node()
+			walkValue(keyOf(prefix, kv), kv.Value())
+		}
+	}
+
+	table := ""
+	arrays := make(map[string]int) // [[array]] 表已出现的次数
+	for p.NextExpression() {
+		expr := p.Expression()
+		switch expr.Kind {
+		case unstable.Table:
+			table = keyOf("", expr)
+		case unstable.ArrayTable:
+			key := keyOf("", expr)
+			table = key + "[" + strconv.Itoa(arrays[key]) + "]"
+			arrays[key]++
+		case unstable.KeyValue:
+			walkValue(keyOf(table, expr), expr.Value())
+		}
+	}
+	return p.Error()
+}
+
+// filePosition 返回 key 在第 i 个配置文件中的位置，首次调用时解析该文件
+func (s *loadState) filePosition(i int, key string) (position, bool) {
+	if s.positions == nil {
+		s.positions = make([]map[string]position, len(s.files))
+	}
+	if s.positions[i] == nil {
+		index, err := filePositions(s.files[i], s.types[i])
+		if err != nil {
+			// 位置只是辅助信息，文件已被 Viper 成功读取，解析失败时忽略
+			index = map[string]position{}
+		}
+		s.positions[i] = index
+	}
+	p, ok := s.positions[i][strings.ToLower(key)]
+	return p, ok
+}
+
+// keyPosition 返回 key 最终生效值所在的文件位置，值来自环境变量或命令行参数时返回 false
+//
+// key 可以指向列表元素 (如 "hosts[0]")，文件中找不到时逐级退回到上层键。
+func (s *loadState) keyPosition(key string) (position, bool) {
+	if s == nil || s.overridden(key) {
+		return position{}, false
+	}
+	for k := key; k != ""; k = parentKey(k) {
+		// 后合并的文件优先
+		for i := len(s.files) - 1; i >= 0; i-- {
+			if p, ok := s.filePosition(i, k); ok {
+				return p, true
+			}
+		}
+	}
+	return position{}, false
+}
+
+// leafOf 返回 key 所属的叶子配置项 (key 可以指向叶子内部，如列表元素)
+func (s *loadState) leafOf(key string) (leafField, bool) {
+	key = strings.ToLower(key)
+	for _, leaf := range collectLeaves(s.typ) {
+		lk := strings.ToLower(leaf.key())
+		if key == lk || strings.HasPrefix(key, lk+".") || strings.HasPrefix(key, lk+"[") {
+			return leaf, true
+		}
+	}
+	return leafField{}, false
+}
+
+// overridden 判断 key 所属的叶子配置项是否由命令行参数或环境变量提供
+func (s *loadState) overridden(key string) bool {
+	leaf, ok := s.leafOf(key)
+	if !ok {
+		return false
+	}
+	switch s.sources[leaf.key()].Kind {
+	case SourceFlag, SourceEnv:
+		return true
+	}
+	return false
+}
+
+// decodeErrors 将 mapstructure 的字段解码错误转换为带文件位置的 DecodeError，其余错误原样保留
+func (s *loadState) decodeErrors(err error) error {
+	var target *mapstructure.DecodeError
+	if !errors.As(err, &target) {
+		return err
+	}
+
+	var errs []error
+	var walk func(err error)
+	walk = func(err error) {
+		if err == nil {
+			return
+		}
+		switch e := err.(type) {
+		case *mapstructure.DecodeError:
+			de := &DecodeError{Key: e.Name(), Err: e.Unwrap()}
+			if p, ok := s.keyPosition(de.Key); ok {
+				de.File, de.Line, de.Column = p.file, p.line, p.column
+			}
+			errs = append(errs, de)
+		case interface{ Unwrap() []error }:
+			for _, e := range e.Unwrap() {
+				walk(e)
+			}
+		case interface{ Unwrap() error }:
+			// mapstructure 在多个错误外层包了一句提示，直接展开
+			walk(e.Unwrap())
+		default:
+			errs = append(errs, err)
+		}
+	}
+	walk(err)
+
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return errors.Join(errs...)
+}
+
+// annotateFields 为验证错误中的每个字段填充其值所在的文件位置，并对敏感字段的值脱敏
+func (s *loadState) annotateFields(err error) {
+	var verr *validator.ValidationError
+	if !errors.As(err, &verr) {
+		return
+	}
+	for i := range verr.Fields {
+		f := &verr.Fields[i]
+		if p, ok := s.keyPosition(f.Path); ok {
+			f.File, f.Line, f.Column = p.file, p.line, p.column
+		}
+		if leaf, ok := s.leafOf(f.Path); ok && isSensitive(leaf.field) {
+			f.Value = secretMask
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package conf
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/oy3o/conf/validator"
+)
+
+func TestFilePositions(t *testing.T) {
+	cases := map[string]string{
+		"config.yaml": "app_name: svc\ndatabase:\n  host: h\n  port: 80\ntags:\n  - a\n  - b\n",
+		"config.json": "{\n  \"app_name\": \"svc\",\n  \"database\": {\n    \"host\": \"h\", \"port\": 80\n  },\n  \"tags\": [\"a\",\n    \"b\"]\n}\n",
+		"config.toml": "app_name = \"svc\"\n\n[database]\nhost = \"h\"\n  port = 80\n",
+	}
+	want := map[string]map[string]position{
+		"config.yaml": {
+			"database.port": {line: 4, column: 3},
+			"tags[1]":       {line: 7, column: 5},
+		},
+		"config.json": {
+			"database.port": {line: 4, column: 18},
+			"tags[1]":       {line: 7, column: 5},
+		},
+		"config.toml": {
+			"database":      {line: 3, column: 2},
+			"database.port": {line: 5, column: 3},
+		},
+	}
+
+	dir := writeFiles(t, cases)
+	for name, keys := range want {
+		index, err := filePositions(filepath.Join(dir, name), strings.TrimPrefix(filepath.Ext(name), "."))
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", name, err)
+		}
+		for key, p := range keys {
+			got := index[key]
+			if got.line != p.line || got.column != p.column {
+				t.Errorf("%s: expected %s at %d:%d, got %d:%d", name, key, p.line, p.column, got.line, got.column)
+			}
+		}
+	}
+}
+
+func TestLoad_DecodeErrorPosition(t *testing.T) {
+	configDir := writeFiles(t, map[string]string{
+		"config.yaml": "database:\n  host: h\n  port: abc\n",
+	})
+
+	_, err := Load[TestConfig]("posapp", WithSearchPaths(configDir), WithProfiles())
+
+	var derr *DecodeError
+	if !errors.As(err, &derr) {
+		t.Fatalf("Expected DecodeError, got %v", err)
+	}
+	if derr.Key != "database.port" || derr.Line != 3 || derr.Column != 3 {
+		t.Errorf("Expected database.port at 3:3, got %+v", derr)
+	}
+	if !strings.Contains(err.Error(), "config.yaml:3:3: 'database.port'") {
+		t.Errorf("Expected file:line:col in message, got: %v", err)
+	}
+}
+
+func TestLoad_ValidationErrorPosition(t *testing.T) {
+	configDir := writeFiles(t, map[string]string{
+		"config.toml": "[database]\nhost = \"h\"\nport = 80\n",
+	})
+
+	_, err := Load[TestConfig]("posapp", WithSearchPaths(configDir), WithFileType("toml"), WithProfiles())
+
+	var verr *validator.ValidationError
+	if !errors.As(err, &verr) || len(verr.Fields) != 1 {
+		t.Fatalf("Expected one field error, got %v", err)
+	}
+	f := verr.Fields[0]
+	if f.Path != "database.port" || filepath.Base(f.File) != "config.toml" || f.Line != 3 || f.Column != 1 {
+		t.Errorf("Expected database.port at config.toml:3:1, got %+v", f)
+	}
+	if !strings.Contains(err.Error(), "config.toml:3:1: database.port: ") {
+		t.Errorf("Expected file:line:col in message, got: %v", err)
+	}
+
+	t.Run("Env Override", func(t *testing.T) {
+		os.Setenv("POSAPP_DATABASE_PORT", "81")
+		defer os.Unsetenv("POSAPP_DATABASE_PORT")
+
+		_, err := Load[TestConfig]("posapp", WithSearchPaths(configDir), WithFileType("toml"), WithProfiles())
+		if !errors.As(err, &verr) || len(verr.Fields) != 1 {
+			t.Fatalf("Expected one field error, got %v", err)
+		}
+		// 值来自环境变量，不应指向文件中被覆盖的位置
+		if verr.Fields[0].File != "" {
+			t.Errorf("Expected no file position for env value, got %+v", verr.Fields[0])
+		}
+	})
+
+	t.Run("Config Type Without Extension", func(t *testing.T) {
+		// 没有扩展名的 config 按 WithFileType 解析，位置也应按同一格式定位
+		configDir := writeFiles(t, map[string]string{
+			"config": "[database]\nhost = \"h\"\nport = 80\n",
+		})
+
+		_, err := Load[TestConfig]("posapp", WithSearchPaths(configDir), WithFileType("toml"), WithProfiles())
+		if !errors.As(err, &verr) || len(verr.Fields) != 1 {
+			t.Fatalf("Expected one field error, got %v", err)
+		}
+		f := verr.Fields[0]
+		if filepath.Base(f.File) != "config" || f.Line != 3 || f.Column != 1 {
+			t.Errorf("Expected database.port at config:3:1, got %+v", f)
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package conf
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// profileList 返回需要叠加在基础配置文件之上的 profile 列表 (按合并顺序)
+// 默认顺序: <env> -> local -> <env>.local，env 来自 GO_ENV / APP_ENV；
+// 通过 WithConfigFile 指定了配置文件时默认不叠加任何 profile
+func (o *options) profileList() []string {
+	if o.profiles != nil {
+		return o.profiles
+	}
+	if o.configFile != "" {
+		return nil
+	}
+	env := currentEnv()
+	if env == "" {
+		return []string{"local"}
+	}
+	return []string{env, "local", env + ".local"}
+}
+
+// findConfigFile 在搜索路径中查找 <name>.<任意支持的扩展名> (与基础配置文件相同)，
+// 返回第一个存在的文件及按其扩展名确定的解析格式
+func (o *options) findConfigFile(name string) (path, configType string, ok bool) {
+	for _, dir := range o.searchPaths {
+		for _, ext := range viper.SupportedExts {
+			path := filepath.Join(dir, name+"."+ext)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path, ext, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// baseConfigFile 返回基础配置文件及其解析格式，查找规则与 Viper 的 ReadInConfig 一致:
+// WithConfigFile 指定的文件按扩展名解析；否则依次在搜索路径中查找 <name>.<任意支持的扩展名> 和 <name>，
+// 统一按 fileType 解析
+func (o *options) baseConfigFile() (path, configType string, ok bool) {
+	if o.configFile != "" {
+		return o.configFile, strings.TrimPrefix(filepath.Ext(o.configFile), "."), true
+	}
+	for _, dir := range o.searchPaths {
+		for _, name := range append(prefixExts(o.fileName, viper.SupportedExts), o.fileName) {
+			path := filepath.Join(dir, name)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path, o.fileType, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+func prefixExts(name string, exts []string) []string {
+	names := make([]string, len(exts))
+	for i, ext := range exts {
+		names[i] = name + "." + ext
+	}
+	return names
+}
+
+// mergeProfiles 依次将 config.<profile>.<ext> 深度合并到配置中，不存在的文件被跳过
+func (s *loadState) mergeProfiles(o *options) error {
+	for _, profile := range o.profileList() {
+		path, configType, ok := o.findConfigFile(o.fileName + "." + profile)
+		if !ok {
+			continue
+		}
+
+		if err := s.mergeFile(path, configType); err != nil {
+			return fmt.Errorf("merge profile config %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// mergeFile 读取配置文件并深度合并到配置中，同时单独保留该文件的内容并记录其提供的配置项来源
+// 后合并的文件覆盖先合并的文件
+func (s *loadState) mergeFile(path, configType string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	// 同一份内容分别解析为单个文件的视图 (来源、未知键检查) 和合并结果
+	fv := viper.New()
+	fv.SetConfigType(configType)
+	if err := fv.ReadConfig(bytes.NewReader(data)); err != nil {
+		return err
+	}
+	s.v.SetConfigType(configType)
+	if err := s.v.MergeConfig(bytes.NewReader(data)); err != nil {
+		return err
+	}
+	s.files = append(s.files, path)
+	s.configs = append(s.configs, fv)
+	s.types = append(s.types, configType)
+
+	for _, leaf := range collectLeaves(s.typ) {
+		if key := leaf.key(); fv.IsSet(key) {
+			s.record(key, Source{Kind: SourceFile, Name: path, Raw: fv.Get(key)})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFiles 在同一个临时目录中写入多个文件
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to create temp config file: %v", err)
+		}
+	}
+	return dir
+}
+
+func TestLoad_ProfileLayering(t *testing.T) {
+	configDir := writeFiles(t, map[string]string{
+		"config.yaml":         "app_name: base\ndatabase:\n  host: base-host\n  port: 3307\n",
+		"config.staging.yaml": "database:\n  host: staging-host\n",
+		"config.local.yaml":   "app_name: local\n",
+	})
+
+	os.Setenv("GO_ENV", "staging")
+	defer os.Unsetenv("GO_ENV")
+
+	cfg, err := Load[TestConfig]("myapp", WithSearchPaths(configDir))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// 深度合并：profile 只覆盖自己声明的 key
+	if cfg.Database.Host != "staging-host" {
+		t.Errorf("Expected host from staging profile, got %s", cfg.Database.Host)
+	}
+	if cfg.Database.Port != 3307 {
+		t.Errorf("Expected port from base file, got %d", cfg.Database.Port)
+	}
+	if cfg.AppName != "local" {
+		t.Errorf("Expected app_name from local profile, got %s", cfg.AppName)
+	}
+
+	t.Run("Explicit Profiles", func(t *testing.T) {
+		cfg, err := Load[TestConfig]("myapp", WithSearchPaths(configDir), WithProfiles())
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if cfg.Database.Host != "base-host" || cfg.AppName != "base" {
+			t.Errorf("Expected only base file to be loaded, got %+v", cfg)
+		}
+	})
+	t.Run("Other Extensions", func(t *testing.T) {
+		// profile 与基础配置文件一样接受任意支持的扩展名，并按扩展名解析
+		configDir := writeFiles(t, map[string]string{
+			"config.yml":            "app_name: base\ndatabase:\n  host: base-host\n",
+			"config.production.yml": "database:\n  host: prod-host\n",
+			"config.local.json":     `{"app_name": "local"}`,
+		})
+
+		cfg, err := Load[TestConfig]("myapp", WithSearchPaths(configDir), WithProfiles("production", "local"))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if cfg.Database.Host != "prod-host" || cfg.AppName != "local" {
+			t.Errorf("Expected values from .yml and .json profiles, got %+v", cfg)
+		}
+	})
+}
+
+func TestLoad_ProfileUnknownKey(t *testing.T) {
+	configDir := writeFiles(t, map[string]string{
+		"config.yaml":       "database:\n  host: base-host\n",
+		"config.local.yaml": "database:\n  hots: typo\n",
+	})
+
+	_, err := Load[TestConfig]("myapp", WithSearchPaths(configDir))
+	if err == nil || !strings.Contains(err.Error(), "hots") {
+		t.Errorf("Expected unknown key error from merged profile, got %v", err)
+	}
+}
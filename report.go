@@ -0,0 +1,86 @@
+package conf
+
+import (
+	"context"
+	"reflect"
+)
+
+// SourceKind 表示配置项的值来源
+type SourceKind string
+
+const (
+	SourceDefault SourceKind = "default" // default 标签
+	SourceFile    SourceKind = "file"    // 配置文件 (含 profile 文件)
+	SourceEnv     SourceKind = "env"     // 环境变量 (含 <NAME>_FILE)
+	SourceFlag    SourceKind = "flag"    // 命令行参数
+)
+
+// Source 描述单个配置项最终生效值的来源
+type Source struct {
+	Kind SourceKind
+	Name string // 文件路径、环境变量名或参数名 (如 "--db.port")，default 来源为空
+	Raw  any    // 解码前的原始值，敏感字段为 "******"
+}
+
+// Report 记录每个配置项的来源，键为点分隔的键路径 (如 "db.port")
+// 未被任何来源设置 (保持零值) 的配置项不会出现在报告中
+type Report struct {
+	Sources map[string]Source
+}
+
+// Source 返回指定配置项的来源
+func (r *Report) Source(key string) (Source, bool) {
+	s, ok := r.Sources[key]
+	return s, ok
+}
+
+// LoadWithReport 加载并验证配置，同时返回每个配置项的来源报告
+func LoadWithReport[T any](appName string, opts ...Option) (*T, *Report, error) {
+	cfg, state, err := load[T](context.Background(), appName, newOptions(opts))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cfg, buildReport(state, reflect.TypeOf(cfg).Elem()), nil
+}
+
+// sourceRank 是各来源的优先级，与 Viper 一致: flag > env > file > default
+var sourceRank = map[SourceKind]int{
+	SourceDefault: 0,
+	SourceFile:    1,
+	SourceEnv:     2,
+	SourceFlag:    3,
+}
+
+// record 记录配置项的一个来源，只保留优先级最高的来源；同级来源 (如后合并的文件) 覆盖先前的记录
+func (s *loadState) record(key string, src Source) {
+	if cur, ok := s.sources[key]; ok && sourceRank[cur.Kind] > sourceRank[src.Kind] {
+		return
+	}
+	s.sources[key] = src
+}
+
+// recordDefaults 为没有其他来源、但带有 default 标签的配置项记录默认值来源
+func (s *loadState) recordDefaults() {
+	for _, leaf := range collectLeaves(s.typ) {
+		if def, ok := leaf.field.Tag.Lookup("default"); ok {
+			s.record(leaf.key(), Source{Kind: SourceDefault, Raw: def})
+		}
+	}
+}
+
+// buildReport 根据加载时记录的来源生成报告，敏感字段的原始值脱敏
+func buildReport(state *loadState, typ reflect.Type) *Report {
+	report := &Report{Sources: make(map[string]Source)}
+	for _, leaf := range collectLeaves(typ) {
+		source, ok := state.sources[leaf.key()]
+		if !ok {
+			continue
+		}
+		if isSensitive(leaf.field) {
+			source.Raw = secretMask
+		}
+		report.Sources[leaf.key()] = source
+	}
+	return report
+}
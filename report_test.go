@@ -0,0 +1,87 @@
+package conf
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadWithReport(t *testing.T) {
+	configDir := writeFiles(t, map[string]string{
+		"config.yaml":       "app_name: from-file\ndatabase:\n  host: base-host\n",
+		"config.local.yaml": "database:\n  host: local-host\n",
+	})
+
+	os.Setenv("REPORTAPP_DATABASE_PASSWORD", "hunter2")
+	defer os.Unsetenv("REPORTAPP_DATABASE_PASSWORD")
+
+	_, report, err := LoadWithReport[TestConfig]("reportapp", WithSearchPaths(configDir))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	cases := map[string]Source{
+		"app_name":          {Kind: SourceFile, Name: filepath.Join(configDir, "config.yaml"), Raw: "from-file"},
+		"database.host":     {Kind: SourceFile, Name: filepath.Join(configDir, "config.local.yaml"), Raw: "local-host"},
+		"database.port":     {Kind: SourceDefault, Raw: "3306"},
+		"database.password": {Kind: SourceEnv, Name: "REPORTAPP_DATABASE_PASSWORD", Raw: secretMask},
+	}
+	for key, want := range cases {
+		got, ok := report.Source(key)
+		if !ok {
+			t.Errorf("Expected source for %s", key)
+			continue
+		}
+		if got != want {
+			t.Errorf("%s: expected %+v, got %+v", key, want, got)
+		}
+	}
+
+	if _, ok := report.Source("debug"); ok {
+		t.Error("Unset keys should not appear in the report")
+	}
+}
+
+func TestLoadWithReport_RecordedAtLoad(t *testing.T) {
+	configDir := writeFiles(t, map[string]string{
+		"config.yaml":       "database:\n  host: base-host\n",
+		"config.local.yaml": "database:\n  port: 5432\n",
+		"db_password":       "from-secret-file\n",
+	})
+
+	os.Setenv("GO_ENV", "production")
+	defer os.Unsetenv("GO_ENV")
+	os.Setenv("RECORDAPP_DATABASE_PASSWORD_FILE", filepath.Join(configDir, "db_password"))
+	defer os.Unsetenv("RECORDAPP_DATABASE_PASSWORD_FILE")
+
+	// 生产环境 strict 检查以记录的来源为准，<NAME>_FILE 提供的值同样满足要求
+	cfg, state, err := load[TestConfig](context.Background(), "recordapp", newOptions([]Option{
+		WithSearchPaths(configDir), WithProfiles("local"),
+	}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// 报告只依赖加载时的记录，之后修改环境变量或删除文件都不影响结果
+	os.Unsetenv("RECORDAPP_DATABASE_PASSWORD_FILE")
+	if err := os.RemoveAll(configDir); err != nil {
+		t.Fatal(err)
+	}
+	report := buildReport(state, reflect.TypeOf(*cfg))
+
+	cases := map[string]Source{
+		"database.host":     {Kind: SourceFile, Name: filepath.Join(configDir, "config.yaml"), Raw: "base-host"},
+		"database.port":     {Kind: SourceFile, Name: filepath.Join(configDir, "config.local.yaml"), Raw: 5432},
+		"database.password": {Kind: SourceEnv, Name: "RECORDAPP_DATABASE_PASSWORD_FILE", Raw: secretMask},
+	}
+	for key, want := range cases {
+		if got, _ := report.Source(key); got != want {
+			t.Errorf("%s: expected %+v, got %+v", key, want, got)
+		}
+	}
+	if cfg.Database.Password != "from-secret-file" {
+		t.Errorf("Expected password from secret file, got %q", cfg.Database.Password)
+	}
+}
@@ -0,0 +1,335 @@
+package conf
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// jsonSchemaDraft 是生成的 JSON Schema 所遵循的规范版本
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema 根据 T 生成 JSON Schema (draft 2020-12)，可用于编辑器补全和 CI 校验配置文件
+//
+// 属性名与加载时一致 (mapstructure > yaml > json > toml > FieldName)，
+// default 标签映射为 default，常用 validate 规则映射为对应的 Schema 关键字，
+// 所有对象均为 additionalProperties: false，与严格解码 (ErrorUnused) 保持一致。
+//
+// required 规则只有在字段既没有 default 标签、也不是 env:"strict" 时才会写入 required，
+// 因为这两类字段本就不应 (或不必) 出现在配置文件中。
+func JSONSchema[T any]() ([]byte, error) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+
+	schema := typeSchema(typ, make(map[reflect.Type]bool))
+	schema["$schema"] = jsonSchemaDraft
+	if name := typ.Name(); name != "" {
+		schema["title"] = name
+	}
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// typeSchema 返回类型本身的 Schema (不含字段级的 validate / default)
+// visiting 记录当前路径上正在展开的结构体类型，引用自身 (或外层) 类型的字段只生成 {"type": "object"}
+func typeSchema(typ reflect.Type, visiting map[reflect.Type]bool) map[string]any {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	switch {
+	case typ == durationType:
+		return map[string]any{"type": "string"}
+	case typ == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case isSecretType(typ):
+		inner := typeSchema(reflect.New(typ).Interface().(secretSetter).innerType(), visiting)
+		inner["writeOnly"] = true
+		return inner
+	case typ.Kind() == reflect.Struct && reflect.PointerTo(typ).Implements(textUnmarshalerType):
+		return map[string]any{"type": "string"}
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string"}
+		}
+		return map[string]any{"type": "array", "items": typeSchema(typ.Elem(), visiting)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": typeSchema(typ.Elem(), visiting)}
+	case reflect.Struct:
+		if visiting[typ] {
+			return map[string]any{"type": "object"}
+		}
+		return structSchema(typ, visiting)
+	default:
+		return map[string]any{}
+	}
+}
+
+func structSchema(typ reflect.Type, visiting map[reflect.Type]bool) map[string]any {
+	visiting[typ] = true
+	defer delete(visiting, typ)
+
+	properties := make(map[string]any)
+	var required []string
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if isSquash(field) && !visiting[derefType(field.Type)] {
+			embedded := structSchema(derefType(field.Type), visiting)
+			for name, prop := range embedded["properties"].(map[string]any) {
+				properties[name] = prop
+			}
+			if names, ok := embedded["required"].([]string); ok {
+				required = append(required, names...)
+			}
+			continue
+		}
+		name := resolveKeyName(field)
+		if name == "" {
+			continue
+		}
+
+		prop := typeSchema(field.Type, visiting)
+		if desc := field.Tag.Get("desc"); desc != "" {
+			prop["description"] = desc
+		}
+		if def, ok := field.Tag.Lookup("default"); ok {
+			if v, ok := parseDefault(field.Type, def); ok {
+				prop["default"] = v
+			}
+		}
+
+		rules, itemRules := splitDive(field.Tag.Get("validate"))
+		if applyRules(prop, field.Type, rules) && !hasDefault(field) && !isStrict(field) {
+			required = append(required, name)
+		}
+		if items, ok := prop["items"].(map[string]any); ok && len(itemRules) > 0 {
+			applyRules(items, field.Type.Elem(), itemRules)
+		}
+
+		properties[name] = prop
+	}
+
+	schema := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func hasDefault(field reflect.StructField) bool {
+	_, ok := field.Tag.Lookup("default")
+	return ok
+}
+
+// splitDive 将 validate 标签拆分为作用于字段本身和作用于元素 (dive 之后) 的规则
+func splitDive(tag string) (rules, itemRules []string) {
+	if tag == "" {
+		return nil, nil
+	}
+	parts := strings.Split(tag, ",")
+	for i, part := range parts {
+		if part == "dive" {
+			return parts[:i], parts[i+1:]
+		}
+	}
+	return parts, nil
+}
+
+// applyRules 将 validate 规则映射为 Schema 关键字，返回是否包含 required
+func applyRules(schema map[string]any, typ reflect.Type, rules []string) bool {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if isSecretType(typ) {
+		typ = reflect.New(typ).Interface().(secretSetter).innerType()
+	}
+
+	required := false
+	for _, rule := range rules {
+		// 含 "|" 的组合规则无法用单个关键字表达，直接跳过
+		if strings.Contains(rule, "|") {
+			continue
+		}
+		tag, param, _ := strings.Cut(rule, "=")
+
+		switch tag {
+		case "required":
+			required = true
+		case "min", "gte":
+			setBound(schema, typ, param, "min")
+		case "max", "lte":
+			setBound(schema, typ, param, "max")
+		case "gt", "lt":
+			setBound(schema, typ, param, tag)
+		case "len":
+			setBound(schema, typ, param, "len")
+		case "eq":
+			// 字符串和布尔值比较的是值本身，数组和 map 比较的是元素个数
+			if typ.Kind() == reflect.String || typ.Kind() == reflect.Bool {
+				if v, ok := parseScalar(typ, param); ok {
+					schema["const"] = v
+				}
+			} else {
+				setBound(schema, typ, param, "len")
+			}
+		case "oneof":
+			var enum []any
+			for _, item := range splitOneOf(param) {
+				if v, ok := parseScalar(typ, item); ok {
+					enum = append(enum, v)
+				}
+			}
+			schema["enum"] = enum
+		case "email":
+			schema["format"] = "email"
+		case "url", "uri":
+			schema["format"] = "uri"
+		case "hostname", "hostname_rfc1123":
+			schema["format"] = "hostname"
+		case "ipv4":
+			schema["format"] = "ipv4"
+		case "ipv6":
+			schema["format"] = "ipv6"
+		case "ip":
+			schema["anyOf"] = []any{
+				map[string]any{"format": "ipv4"},
+				map[string]any{"format": "ipv6"},
+			}
+		}
+	}
+	return required
+}
+
+// boundKeywords 记录边界规则在不同类型上对应的关键字: 数值、字符串、数组、对象
+var boundKeywords = map[string][4]string{
+	"min": {"minimum", "minLength", "minItems", "minProperties"},
+	"max": {"maximum", "maxLength", "maxItems", "maxProperties"},
+	"gt":  {"exclusiveMinimum"},
+	"lt":  {"exclusiveMaximum"},
+	"len": {"const"}, // 长度类由 min + max 同时表达
+}
+
+// setBound 根据字段类型选择数值、长度、元素个数或属性个数关键字
+func setBound(schema map[string]any, typ reflect.Type, param, bound string) {
+	if bound == "len" && !isNumber(typ) {
+		setBound(schema, typ, param, "min")
+		setBound(schema, typ, param, "max")
+		return
+	}
+
+	keywords := boundKeywords[bound]
+	var key string
+	switch {
+	case typ == durationType:
+		return // Duration 在配置文件中是字符串 ("5s")，无法用数值约束表达
+	case isNumber(typ):
+		if n, err := strconv.ParseFloat(param, 64); err == nil {
+			schema[keywords[0]] = n
+		}
+		return
+	case typ.Kind() == reflect.String:
+		key = keywords[1]
+	case typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array:
+		key = keywords[2]
+	case typ.Kind() == reflect.Map:
+		key = keywords[3]
+	}
+
+	if n, err := strconv.Atoi(param); err == nil && key != "" {
+		schema[key] = n
+	}
+}
+
+func isNumber(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// splitOneOf 拆分 oneof 参数，支持单引号包裹含空格的值: oneof='a b' c
+func splitOneOf(param string) []string {
+	var items []string
+	for param = strings.TrimSpace(param); param != ""; param = strings.TrimSpace(param) {
+		if param[0] == '\'' {
+			if end := strings.IndexByte(param[1:], '\''); end != -1 {
+				items = append(items, param[1:end+1])
+				param = param[end+2:]
+				continue
+			}
+		}
+		item, rest, _ := strings.Cut(param, " ")
+		items = append(items, item)
+		param = rest
+	}
+	return items
+}
+
+// parseDefault 将 default 标签转换为与字段类型匹配的 JSON 值
+func parseDefault(typ reflect.Type, def string) (any, bool) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	switch typ.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+		if typ == timeType {
+			return def, true
+		}
+		var v any
+		if err := json.Unmarshal([]byte(def), &v); err != nil {
+			return nil, false
+		}
+		return v, true
+	}
+	return parseScalar(typ, def)
+}
+
+func parseScalar(typ reflect.Type, s string) (any, bool) {
+	if typ == durationType {
+		return s, true
+	}
+	switch typ.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		return b, err == nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, 64)
+		return i, err == nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, 64)
+		return u, err == nil
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		return f, err == nil
+	default:
+		return s, true
+	}
+}
@@ -0,0 +1,148 @@
+package conf
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type SchemaConfig struct {
+	Name  string   `mapstructure:"name" validate:"required,min=3,max=32" desc:"service name"`
+	Mode  string   `mapstructure:"mode" validate:"oneof=dev prod" default:"dev"`
+	Port  int      `mapstructure:"port" validate:"required,min=1024" default:"8080"`
+	Email string   `mapstructure:"email" validate:"email"`
+	Hosts []string `mapstructure:"hosts" validate:"min=1,dive,hostname"`
+	DB    Database `mapstructure:"db"`
+}
+
+func TestJSONSchema(t *testing.T) {
+	data, err := JSONSchema[SchemaConfig]()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("Expected valid JSON, got %v", err)
+	}
+
+	if schema["$schema"] != jsonSchemaDraft || schema["additionalProperties"] != false {
+		t.Errorf("Unexpected root schema: %v", schema)
+	}
+	// port 有默认值，不要求出现在配置文件中
+	if !reflect.DeepEqual(schema["required"], []any{"name"}) {
+		t.Errorf("Expected only name to be required, got %v", schema["required"])
+	}
+
+	props := schema["properties"].(map[string]any)
+	expect := map[string]map[string]any{
+		"name":  {"type": "string", "minLength": 3.0, "maxLength": 32.0, "description": "service name"},
+		"mode":  {"type": "string", "enum": []any{"dev", "prod"}, "default": "dev"},
+		"port":  {"type": "integer", "minimum": 1024.0, "default": 8080.0},
+		"email": {"type": "string", "format": "email"},
+		"hosts": {"type": "array", "minItems": 1.0, "items": map[string]any{"type": "string", "format": "hostname"}},
+	}
+	for name, want := range expect {
+		if got := props[name]; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: expected %v, got %v", name, want, got)
+		}
+	}
+
+	db := props["db"].(map[string]any)
+	if db["additionalProperties"] != false {
+		t.Error("Nested objects should forbid additional properties")
+	}
+	// password 是 env:"strict" 字段，不应要求写在配置文件中
+	if !reflect.DeepEqual(db["required"], []any{"host"}) {
+		t.Errorf("Expected db.host to be required, got %v", db["required"])
+	}
+}
+
+func TestJSONSchema_Eq(t *testing.T) {
+	type EqConfig struct {
+		Env     string   `mapstructure:"env" validate:"eq=prod"`
+		Code    string   `mapstructure:"code" validate:"eq=5"`
+		Enabled bool     `mapstructure:"enabled" validate:"eq=true"`
+		Replica int      `mapstructure:"replica" validate:"eq=3"`
+		Zones   []string `mapstructure:"zones" validate:"eq=2"`
+		ID      string   `mapstructure:"id" validate:"len=8"`
+	}
+
+	data, err := JSONSchema[EqConfig]()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("Expected valid JSON, got %v", err)
+	}
+
+	props := schema["properties"].(map[string]any)
+	expect := map[string]map[string]any{
+		"env":     {"type": "string", "const": "prod"},
+		"code":    {"type": "string", "const": "5"},
+		"enabled": {"type": "boolean", "const": true},
+		"replica": {"type": "integer", "const": 3.0},
+		"zones":   {"type": "array", "items": map[string]any{"type": "string"}, "minItems": 2.0, "maxItems": 2.0},
+		"id":      {"type": "string", "minLength": 8.0, "maxLength": 8.0},
+	}
+	for name, want := range expect {
+		if got := props[name]; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: expected %v, got %v", name, want, got)
+		}
+	}
+}
+
+func TestJSONSchema_RecursiveType(t *testing.T) {
+	type Node struct {
+		Name     string  `mapstructure:"name"`
+		Child    *Node   `mapstructure:"child"`
+		Children []*Node `mapstructure:"children"`
+	}
+
+	data, err := JSONSchema[Node]()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("Expected valid JSON, got %v", err)
+	}
+
+	// 引用自身的字段不再展开
+	props := schema["properties"].(map[string]any)
+	if want := map[string]any{"type": "object"}; !reflect.DeepEqual(props["child"], want) {
+		t.Errorf("child: expected %v, got %v", want, props["child"])
+	}
+	if want := map[string]any{"type": "array", "items": map[string]any{"type": "object"}}; !reflect.DeepEqual(props["children"], want) {
+		t.Errorf("children: expected %v, got %v", want, props["children"])
+	}
+}
+
+func TestJSONSchema_SquashedEmbed(t *testing.T) {
+	type Base struct {
+		Host string `mapstructure:"host" validate:"required"`
+	}
+	type SquashConfig struct {
+		Base `mapstructure:",squash"`
+		Port int `mapstructure:"port" validate:"required"`
+	}
+
+	data, err := JSONSchema[SquashConfig]()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("Expected valid JSON, got %v", err)
+	}
+
+	// squash 嵌入的字段并入外层对象
+	props := schema["properties"].(map[string]any)
+	if len(props) != 2 || props["host"] == nil || props["port"] == nil {
+		t.Errorf("Expected properties host and port, got %v", props)
+	}
+	if !reflect.DeepEqual(schema["required"], []any{"host", "port"}) {
+		t.Errorf("Expected host and port to be required, got %v", schema["required"])
+	}
+}
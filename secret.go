@@ -0,0 +1,177 @@
+package conf
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/oy3o/conf/validator"
+)
+
+// secretMask 是 Secret 在任何输出中的占位文本
+const secretMask = "******"
+
+// Secret 包装敏感配置值 (密码、Token 等)，防止其出现在日志和序列化结果中
+//
+// Secret 可以像普通字段一样通过配置文件和环境变量加载，validate 标签作用于内部值：
+//
+//	Password conf.Secret[string] `mapstructure:"password" validate:"required,min=8" env:"strict"`
+//
+// String、Format (%v, %+v, %#v ...)、MarshalJSON、MarshalText 和 slog 输出均为 "******"，
+// 只有 Reveal 能取得明文。
+type Secret[T any] struct {
+	value T
+}
+
+// NewSecret 用明文构造 Secret
+func NewSecret[T any](value T) Secret[T] {
+	return Secret[T]{value: value}
+}
+
+// Reveal 返回明文，这是取得内部值的唯一途径
+func (s Secret[T]) Reveal() T {
+	return s.value
+}
+
+func (s Secret[T]) String() string {
+	return secretMask
+}
+
+func (s Secret[T]) GoString() string {
+	return secretMask
+}
+
+// Format 让所有格式化动词 (包括 %+v 和 %#v) 都只输出占位文本
+func (s Secret[T]) Format(f fmt.State, verb rune) {
+	io.WriteString(f, secretMask)
+}
+
+func (s Secret[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(secretMask)
+}
+
+func (s Secret[T]) MarshalText() ([]byte, error) {
+	return []byte(secretMask), nil
+}
+
+func (s Secret[T]) LogValue() slog.Value {
+	return slog.StringValue(secretMask)
+}
+
+// secretValue 由所有 Secret[T] 实现，供包内的解码、验证与导出逻辑使用
+type secretValue interface {
+	revealAny() any
+}
+
+// secretSetter 由 *Secret[T] 实现
+type secretSetter interface {
+	innerType() reflect.Type
+	setAny(v any)
+}
+
+func (s Secret[T]) revealAny() any {
+	return s.value
+}
+
+func (s *Secret[T]) innerType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func (s *Secret[T]) setAny(v any) {
+	s.value = v.(T)
+}
+
+var secretSetterType = reflect.TypeOf((*secretSetter)(nil)).Elem()
+
+// isSecretType 判断类型是否为 Secret[T]
+func isSecretType(typ reflect.Type) bool {
+	return reflect.PointerTo(typ).Implements(secretSetterType)
+}
+
+// secretDecodeHook 让 mapstructure 先按内部类型解码，再包装为 Secret[T]
+func secretDecodeHook(from, to reflect.Type, data any) (any, error) {
+	if from == to || !isSecretType(to) {
+		return data, nil
+	}
+
+	target := reflect.New(to)
+	setter := target.Interface().(secretSetter)
+
+	inner := reflect.New(setter.innerType())
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result:           inner.Interface(),
+		WeaklyTypedInput: true, // 与 Viper 一致，允许环境变量中的字符串转为数字等
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			mapstructure.StringToTimeDurationHookFunc(),
+			mapstructure.StringToSliceHookFunc(","),
+		),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := dec.Decode(data); err != nil {
+		return nil, err
+	}
+
+	setter.setAny(inner.Elem().Interface())
+	return target.Elem().Interface(), nil
+}
+
+// sharedSecretTypes 记录已在 WithValidator 共享的验证器上注册过的 Secret[T] 类型
+var sharedSecretTypes sync.Map // sharedSecretType -> struct{}
+
+type sharedSecretType struct {
+	val *validator.Validator
+	typ reflect.Type
+}
+
+// registerSecretTypes 让验证器按内部值验证 typ 中出现的所有 Secret[T]
+// shared 表示验证器由调用方持有，此时每个类型只注册一次，避免每次 Load 都修改调用方的验证器
+func registerSecretTypes(val *validator.Validator, typ reflect.Type, shared bool) {
+	var types []any
+	collectSecretTypes(typ, map[reflect.Type]bool{}, &types)
+	if shared {
+		types = slices.DeleteFunc(types, func(t any) bool {
+			_, loaded := sharedSecretTypes.LoadOrStore(sharedSecretType{val, reflect.TypeOf(t)}, struct{}{})
+			return loaded
+		})
+	}
+	if len(types) == 0 {
+		return
+	}
+
+	val.RegisterCustomTypeFunc(func(field reflect.Value) any {
+		return field.Interface().(secretValue).revealAny()
+	}, types...)
+}
+
+func collectSecretTypes(typ reflect.Type, seen map[reflect.Type]bool, types *[]any) {
+	if seen[typ] {
+		return
+	}
+	seen[typ] = true
+
+	if isSecretType(typ) {
+		*types = append(*types, reflect.Zero(typ).Interface())
+		return
+	}
+
+	switch typ.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		collectSecretTypes(typ.Elem(), seen, types)
+	case reflect.Struct:
+		if typ == timeType {
+			return
+		}
+		for i := 0; i < typ.NumField(); i++ {
+			if typ.Field(i).IsExported() {
+				collectSecretTypes(typ.Field(i).Type, seen, types)
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package conf
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/oy3o/conf/validator"
+)
+
+type SecretConfig struct {
+	User     string         `mapstructure:"user"`
+	Password Secret[string] `mapstructure:"password" validate:"required,min=8"`
+	Pin      Secret[int]    `mapstructure:"pin"`
+}
+
+func TestLoad_Secret(t *testing.T) {
+	configDir := createConfigFile(t, "config.yaml", "user: admin\npassword: \"s3cr3t-pass\"\n")
+
+	os.Setenv("SECRETAPP_PIN", "1234")
+	defer os.Unsetenv("SECRETAPP_PIN")
+
+	cfg, err := Load[SecretConfig]("secretapp", WithSearchPaths(configDir))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if cfg.Password.Reveal() != "s3cr3t-pass" {
+		t.Errorf("Expected password to decode, got %q", cfg.Password.Reveal())
+	}
+	if cfg.Pin.Reveal() != 1234 {
+		t.Errorf("Expected pin decoded from env, got %d", cfg.Pin.Reveal())
+	}
+
+	t.Run("Validation Uses Inner Value", func(t *testing.T) {
+		configDir := createConfigFile(t, "config.yaml", "password: short\n")
+
+		_, err := Load[SecretConfig]("secretapp", WithSearchPaths(configDir), WithLocale("en"))
+		if err == nil || !strings.Contains(err.Error(), "password") {
+			t.Fatalf("Expected min validation error on password, got %v", err)
+		}
+		if strings.Contains(err.Error(), "short") {
+			t.Errorf("Validation error must not leak the secret, got %v", err)
+		}
+
+		// 结构化错误中的 Value 同样需要脱敏
+		data, _ := json.Marshal(err)
+		if strings.Contains(string(data), "short") || !strings.Contains(string(data), secretMask) {
+			t.Errorf("Structured validation error must redact the secret, got %s", data)
+		}
+	})
+
+	t.Run("Custom Message Redacts Value", func(t *testing.T) {
+		type MsgConfig struct {
+			Password Secret[string] `mapstructure:"password" validate:"min=8" msg:"{field} too short: {value}"`
+			Token    string         `mapstructure:"token" validate:"len=8" secret:"true" msg:"bad {field}: {value}"`
+		}
+		configDir := createConfigFile(t, "config.yaml", "password: short\ntoken: hunter2\n")
+
+		_, err := Load[MsgConfig]("secretapp", WithSearchPaths(configDir))
+		if err == nil || !strings.Contains(err.Error(), "password too short: "+secretMask) {
+			t.Errorf("Expected masked custom message, got %v", err)
+		}
+		if err == nil || !strings.Contains(err.Error(), "bad token: "+secretMask) || strings.Contains(err.Error(), "hunter2") {
+			t.Errorf("Expected masked secret:\"true\" value, got %v", err)
+		}
+	})
+
+	t.Run("Shared Validator Is Not Modified", func(t *testing.T) {
+		type MsgConfig struct {
+			Password Secret[string] `mapstructure:"password" validate:"min=8" msg:"{field} too short: {value}"`
+		}
+		type Request struct {
+			Key      string `json:"key" validate:"len=8" secret:"true" msg:"bad key {value}"`
+			Internal string `json:"internal" validate:"len=2" msg:"bad internal {value}"`
+		}
+
+		val, err := validator.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		val.RegisterRedactor(func(field reflect.StructField) bool { return field.Name == "Internal" })
+
+		configDir := createConfigFile(t, "config.yaml", "password: short\n")
+		for i := 0; i < 2; i++ {
+			_, err := Load[MsgConfig]("secretapp", WithSearchPaths(configDir), WithValidator(val))
+			if err == nil || !strings.Contains(err.Error(), "password too short: "+secretMask) {
+				t.Fatalf("Expected masked custom message, got %v", err)
+			}
+		}
+
+		// 调用方注册的判断仍然生效，conf 的脱敏策略不作用于其他结构体
+		verr, ok := val.Validate(&Request{Key: "hunter2", Internal: "abc"}).(*validator.ValidationError)
+		if !ok {
+			t.Fatal("Expected *ValidationError")
+		}
+		if got := verr.Errors["key"]; got != "bad key hunter2" {
+			t.Errorf("Expected conf policy not to leak into the shared validator, got %q", got)
+		}
+		if got := verr.Errors["internal"]; got != "bad internal "+secretMask {
+			t.Errorf("Expected caller's redactor to be kept, got %q", got)
+		}
+	})
+}
+
+func TestSecret_Redaction(t *testing.T) {
+	cfg := SecretConfig{User: "admin", Password: NewSecret("plaintext")}
+
+	outputs := map[string]string{
+		"%v":  fmt.Sprintf("%v", cfg),
+		"%+v": fmt.Sprintf("%+v", cfg),
+		"%#v": fmt.Sprintf("%#v", cfg),
+		"%s":  fmt.Sprintf("%s", cfg.Password),
+	}
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputs["json"] = string(data)
+
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("config", "password", cfg.Password)
+	outputs["slog"] = buf.String()
+
+	for name, out := range outputs {
+		if strings.Contains(out, "plaintext") {
+			t.Errorf("%s output leaked the secret: %s", name, out)
+		}
+		if !strings.Contains(out, secretMask) {
+			t.Errorf("%s output should contain the mask, got: %s", name, out)
+		}
+	}
+}
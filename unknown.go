@@ -0,0 +1,157 @@
+package conf
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/oy3o/conf/validator"
+)
+
+// UnknownKey 描述配置文件中无法对应到任何字段的键
+type UnknownKey struct {
+	Key        string // 点分隔的键路径 (Viper 统一为小写)
+	Suggestion string // 编辑距离最近的有效键，没有足够接近的候选时为空
+	File       string // 键所在的配置文件
+	Line       int    // 键在文件中的行号，无法定位时为 0
+	Column     int    // 键在文件中的列号，无法定位时为 0
+}
+
+// UnknownKeyError 表示配置文件包含结构体中不存在的键 (通常是拼写错误)
+type UnknownKeyError struct {
+	Keys []UnknownKey
+
+	locale string
+}
+
+// unknownKeyMessages 是 UnknownKeyError 的文案，按语言区分
+var unknownKeyMessages = map[string]struct {
+	title, suggest string
+}{
+	"zh":    {title: "存在未知配置项:", suggest: "，是否应为 '%s'?"},
+	"zh_tw": {title: "存在未知設定項:", suggest: "，是否應為 '%s'?"},
+	"en":    {title: "unknown config keys:", suggest: ", did you mean '%s'?"},
+	"ja":    {title: "不明な設定キーがあります:", suggest: "、'%s' の誤りではありませんか?"},
+	"ko":    {title: "알 수 없는 설정 키:", suggest: ", '%s'을(를) 의도하셨나요?"},
+	"de":    {title: "unbekannte Konfigurationsschlüssel:", suggest: ", meinten Sie '%s'?"},
+	"fr":    {title: "clés de configuration inconnues :", suggest: ", vouliez-vous dire '%s' ?"},
+	"es":    {title: "claves de configuración desconocidas:", suggest: ", ¿quiso decir '%s'?"},
+	"pt":    {title: "chaves de configuração desconhecidas:", suggest: ", você quis dizer '%s'?"},
+}
+
+func (e *UnknownKeyError) Error() string {
+	msg := localized(unknownKeyMessages, e.locale)
+
+	var b strings.Builder
+	b.WriteString(msg.title)
+	for _, k := range e.Keys {
+		if k.Line > 0 {
+			fmt.Fprintf(&b, "\n - %s (%s:%d:%d)", k.Key, k.File, k.Line, k.Column)
+		} else {
+			fmt.Fprintf(&b, "\n - %s (%s)", k.Key, k.File)
+		}
+		if k.Suggestion != "" {
+			fmt.Fprintf(&b, msg.suggest, k.Suggestion)
+		}
+	}
+	return b.String()
+}
+
+// checkUnknownKeys 逐个检查已加载的配置文件，找出无法对应到 typ 中任何字段的键
+//
+// 解码时的 ErrorUnused 只能给出合并后的原始键名，这里额外给出来源文件和拼写建议。
+// 列表元素内部的键不会被 Viper 展开，仍由 ErrorUnused 兜底。
+func checkUnknownKeys(state *loadState, locale string) error {
+	var known []string
+	for _, leaf := range collectLeaves(state.typ) {
+		known = append(known, strings.ToLower(leaf.key()))
+	}
+
+	err := &UnknownKeyError{locale: locale}
+	for i, path := range state.files {
+		keys := state.configs[i].AllKeys()
+		sort.Strings(keys)
+		for _, key := range keys {
+			if isKnownKey(key, known) {
+				continue
+			}
+			uk := UnknownKey{Key: key, Suggestion: suggestKey(key, known), File: path}
+			if p, ok := state.filePosition(i, key); ok {
+				uk.Line, uk.Column = p.line, p.column
+			}
+			err.Keys = append(err.Keys, uk)
+		}
+	}
+
+	if len(err.Keys) == 0 {
+		return nil
+	}
+	return err
+}
+
+// isKnownKey 判断 key 是否为叶子配置项、配置段，或 map 等叶子配置项内部的键
+func isKnownKey(key string, known []string) bool {
+	for _, k := range known {
+		if key == k || strings.HasPrefix(key, k+".") || strings.HasPrefix(k, key+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// suggestKey 返回与 key 编辑距离最近的有效键，距离超过键长的三分之一 (至少为 2) 时不给出建议
+func suggestKey(key string, known []string) string {
+	maxDist := len([]rune(key)) / 3
+	if maxDist < 2 {
+		maxDist = 2
+	}
+
+	best, bestDist := "", maxDist+1
+	for _, k := range known {
+		if d := editDistance(key, k); d < bestDist {
+			best, bestDist = k, d
+		}
+	}
+	return best
+}
+
+// editDistance 计算两个字符串的编辑距离，相邻字符交换计为一次编辑 (如 hots -> host)
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	prev2 := make([]int, len(t)+1)
+	prev := make([]int, len(t)+1)
+	cur := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		cur[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(t)]
+}
+
+// localized 按 "zh_tw" -> "zh" -> "en" 的顺序查找 locale 对应的文案
+func localized[V any](messages map[string]V, locale string) V {
+	name := validator.NormalizeLocale(locale)
+	if msg, ok := messages[name]; ok {
+		return msg
+	}
+	if i := strings.IndexByte(name, '_'); i >= 0 {
+		if msg, ok := messages[name[:i]]; ok {
+			return msg
+		}
+	}
+	return messages["en"]
+}
@@ -0,0 +1,144 @@
+package conf
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/oy3o/conf/validator"
+)
+
+func TestLoad_UnknownKeySuggestion(t *testing.T) {
+	configDir := writeFiles(t, map[string]string{
+		"config.yaml": "database:\n  hots: localhost\n  port: 3307\nzzz: 1\n",
+	})
+
+	_, err := Load[TestConfig]("myapp", WithSearchPaths(configDir), WithProfiles(), WithLocale("en"))
+
+	var uerr *UnknownKeyError
+	if !errors.As(err, &uerr) {
+		t.Fatalf("Expected UnknownKeyError, got %v", err)
+	}
+	if len(uerr.Keys) != 2 {
+		t.Fatalf("Expected 2 unknown keys, got %+v", uerr.Keys)
+	}
+
+	want := UnknownKey{Key: "database.hots", Suggestion: "database.host", File: filepath.Join(configDir, "config.yaml"), Line: 2, Column: 3}
+	if uerr.Keys[0] != want {
+		t.Errorf("Expected %+v, got %+v", want, uerr.Keys[0])
+	}
+	if uerr.Keys[1].Key != "zzz" || uerr.Keys[1].Suggestion != "" {
+		t.Errorf("Expected no suggestion for unrelated key, got %+v", uerr.Keys[1])
+	}
+	if !strings.Contains(err.Error(), "did you mean 'database.host'?") {
+		t.Errorf("Expected English suggestion, got: %v", err)
+	}
+}
+
+func TestLoad_UnknownKeyLocalized(t *testing.T) {
+	configDir := writeFiles(t, map[string]string{
+		"config.yaml":       "database:\n  host: base-host\n",
+		"config.local.yaml": "database:\n  prot: 3307\n",
+	})
+
+	_, err := Load[TestConfig]("myapp", WithSearchPaths(configDir))
+
+	var uerr *UnknownKeyError
+	if !errors.As(err, &uerr) || len(uerr.Keys) != 1 {
+		t.Fatalf("Expected one unknown key, got %v", err)
+	}
+	// 报告键实际所在的 profile 文件
+	if filepath.Base(uerr.Keys[0].File) != "config.local.yaml" {
+		t.Errorf("Expected key attributed to config.local.yaml, got %s", uerr.Keys[0].File)
+	}
+	if !strings.Contains(err.Error(), "是否应为 'database.port'") {
+		t.Errorf("Expected Chinese suggestion, got: %v", err)
+	}
+}
+
+func TestLoad_SquashedEmbed(t *testing.T) {
+	type Base struct {
+		Host string `mapstructure:"host"`
+		Port int    `mapstructure:"port" default:"80"`
+	}
+	type SquashConfig struct {
+		Base `mapstructure:",squash"`
+		Name string `mapstructure:"name"`
+	}
+
+	configDir := writeFiles(t, map[string]string{
+		"config.yaml": "host: file-host\nname: app\n",
+	})
+	t.Setenv("SQUASH_PORT", "8080")
+
+	cfg, err := Load[SquashConfig]("squash", WithSearchPaths(configDir))
+	if err != nil {
+		t.Fatalf("Expected squashed keys to be known, got %v", err)
+	}
+	if cfg.Host != "file-host" || cfg.Port != 8080 || cfg.Name != "app" {
+		t.Errorf("Unexpected config: %+v", cfg)
+	}
+
+	var keys []string
+	for _, leaf := range collectLeaves(reflect.TypeOf(SquashConfig{})) {
+		keys = append(keys, leaf.key())
+	}
+	if strings.Join(keys, ",") != "host,port,name" {
+		t.Errorf("Expected squashed leaves at the parent path, got %v", keys)
+	}
+	t.Run("Validation Path", func(t *testing.T) {
+		type RuleBase struct {
+			Port int `mapstructure:"port" validate:"min=1024"`
+		}
+		type RuleConfig struct {
+			RuleBase `mapstructure:",squash"`
+		}
+		configDir := writeFiles(t, map[string]string{"config.yaml": "port: 80\n"})
+
+		_, err := Load[RuleConfig]("squashrule", WithSearchPaths(configDir))
+		var verr *validator.ValidationError
+		if !errors.As(err, &verr) || len(verr.Fields) != 1 {
+			t.Fatalf("Expected one validation error, got %v", err)
+		}
+		f := verr.Fields[0]
+		if f.Path != "port" || filepath.Base(f.File) != "config.yaml" || f.Line != 1 || f.Column != 1 {
+			t.Errorf("Expected error at port (config.yaml:1:1), got %+v", f)
+		}
+	})
+}
+
+func TestEditDistance(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"host", "host", 0},
+		{"hots", "host", 1},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+	}
+	for _, c := range cases {
+		if got := editDistance(c.a, c.b); got != c.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestUnknownKeyError_Locales(t *testing.T) {
+	err := &UnknownKeyError{Keys: []UnknownKey{{Key: "db.hots", Suggestion: "db.host", File: "config.yaml"}}}
+
+	cases := map[string]string{
+		"zh-TW": "是否應為 'db.host'",
+		"ja-JP": "'db.host' の誤りではありませんか",
+		"de":    "meinten Sie 'db.host'",
+		"xx":    "did you mean 'db.host'",
+	}
+	for locale, want := range cases {
+		err.locale = locale
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected %q, got %s", locale, want, err.Error())
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package validator
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type localeKey struct{}
+
+type redactorKey struct{}
+
+// ContextWithLocale 返回携带错误信息语言的 context，供 ValidateCtx 使用
+func ContextWithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFromContext 返回 ContextWithLocale 设置的语言，未设置时为空
+func LocaleFromContext(ctx context.Context) string {
+	locale, _ := ctx.Value(localeKey{}).(string)
+	return locale
+}
+
+// ContextWithRedactor 返回携带敏感字段判断的 context，只作用于使用该 ctx 的 ValidateCtx / ValidateDeepCtx
+// 与 RegisterRedactor 注册的判断同时生效，任一返回 true 的字段即被脱敏；不会修改 Validator 本身
+func ContextWithRedactor(ctx context.Context, fn func(field reflect.StructField) bool) context.Context {
+	return context.WithValue(ctx, redactorKey{}, fn)
+}
+
+// redactorFromContext 返回 ContextWithRedactor 设置的判断，未设置时为 nil
+func redactorFromContext(ctx context.Context) func(field reflect.StructField) bool {
+	fn, _ := ctx.Value(redactorKey{}).(func(field reflect.StructField) bool)
+	return fn
+}
+
+// ValidateCtx 与 Validate 相同，但将 ctx 传给 ContextValidatable 和 RegisterRuleCtx 注册的规则
+//
+// 错误信息语言取自 ctx (见 ContextWithLocale)。ctx 已取消或超时时返回 ctx.Err()。
+func (v *Validator) ValidateCtx(ctx context.Context, i interface{}) error {
+	return v.validateWith(ctx, i, LocaleFromContext(ctx))
+}
+
+// ParseAcceptLanguage 解析 HTTP Accept-Language 头，按权重从高到低返回语言标签
+//
+// 权重相同的标签保持原有顺序，q=0 和通配符 "*" 被忽略，例如
+// "fr-CH, fr;q=0.9, en;q=0.8, *;q=0.5" -> ["fr-CH", "fr", "en"]。
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var items []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || strings.TrimSpace(name) != "q" {
+				continue
+			}
+			if f, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				q = f
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		items = append(items, weighted{tag: tag, q: q})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool { return items[i].q > items[j].q })
+
+	tags := make([]string, len(items))
+	for i, item := range items {
+		tags[i] = item.tag
+	}
+	return tags
+}
@@ -0,0 +1,174 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// SelfTag 是 ValidateDeep 中 Validate() 方法返回的错误在 FieldError.Tag 中的取值
+const SelfTag = "self"
+
+var (
+	selfValidatableType    = reflect.TypeOf((*SelfValidatable)(nil)).Elem()
+	contextValidatableType = reflect.TypeOf((*ContextValidatable)(nil)).Elem()
+)
+
+// ValidateDeep 对整棵结构体树执行验证
+//
+// 先对全部字段执行 validate 标签验证 (即使根类型实现了 SelfValidatable)，
+// 再依次调用根值以及每个嵌套结构体、指针、切片元素和 map 值上的 Validate() 方法
+// (SelfValidatable 或 ContextValidatable)。
+// Validate() 返回的错误记录在其所在的键路径上 (Tag 为 SelfTag)；
+// 返回 *ValidationError 时，其中每个字段的路径会加上所在位置的前缀。
+func (v *Validator) ValidateDeep(i interface{}) error {
+	return v.ValidateDeepCtx(context.Background(), i)
+}
+
+// ValidateDeepCtx 与 ValidateDeep 相同，但将 ctx 传给 ContextValidatable 和 RegisterRuleCtx 注册的规则
+// 错误信息语言取自 ctx (见 ContextWithLocale)。ctx 取消或超时后不再调用剩余的 Validate()，并返回 ctx.Err()。
+func (v *Validator) ValidateDeepCtx(ctx context.Context, i interface{}) error {
+	trans, lang, err := v.translator(LocaleFromContext(ctx))
+	if err != nil {
+		return err
+	}
+	err = v.validateTags(ctx, i, trans, lang)
+	verr, ok := err.(*ValidationError)
+	if err != nil && !ok {
+		return err
+	}
+	if verr == nil {
+		verr = &ValidationError{Errors: make(map[string]string)}
+	}
+
+	walkSelfValidatable(ctx, reflect.ValueOf(i), "", verr.addSelfError)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if len(verr.Fields) == 0 {
+		return nil
+	}
+	return verr
+}
+
+// addSelfError 记录 path 处 Validate() 返回的错误
+func (e *ValidationError) addSelfError(path string, err error) {
+	var inner *ValidationError
+	if !errors.As(err, &inner) {
+		e.add(FieldError{Path: path, Tag: SelfTag, Message: err.Error()})
+		return
+	}
+	for _, f := range inner.fieldList() {
+		f.Path = joinPath(path, f.Path)
+		e.add(f)
+	}
+}
+
+// add 追加字段错误，同一路径已有错误时在 Errors 中合并信息
+func (e *ValidationError) add(f FieldError) {
+	e.Fields = append(e.Fields, f)
+	if msg, ok := e.Errors[f.Path]; ok {
+		e.Errors[f.Path] = msg + "; " + f.Message
+	} else {
+		e.Errors[f.Path] = f.Message
+	}
+}
+
+func joinPath(prefix, path string) string {
+	switch {
+	case prefix == "":
+		return path
+	case path == "":
+		return prefix
+	case path[0] == '[':
+		return prefix + path
+	default:
+		return prefix + "." + path
+	}
+}
+
+// walkSelfValidatable 按字段声明顺序遍历 val，对每个实现了 SelfValidatable 或 ContextValidatable 的值调用 Validate()
+// ctx 取消或超时后停止遍历
+func walkSelfValidatable(ctx context.Context, val reflect.Value, path string, report func(path string, err error)) {
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return
+		}
+		val = val.Elem()
+	}
+	if ctx.Err() != nil {
+		return
+	}
+
+	if validate, ok := selfValidatable(val); ok {
+		if err := validate(ctx); err != nil && ctx.Err() == nil {
+			report(path, err)
+		}
+	}
+
+	switch val.Kind() {
+	case reflect.Struct:
+		typ := val.Type()
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if isSquash(field) {
+				walkSelfValidatable(ctx, val.Field(i), path, report)
+				continue
+			}
+			if name := fieldName(field); name != "" {
+				walkSelfValidatable(ctx, val.Field(i), joinPath(path, name), report)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < val.Len(); i++ {
+			walkSelfValidatable(ctx, val.Index(i), fmt.Sprintf("%s[%d]", path, i), report)
+		}
+	case reflect.Map:
+		// map 无序，按键排序保证错误顺序稳定
+		keys := val.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for _, key := range keys {
+			walkSelfValidatable(ctx, val.MapIndex(key), fmt.Sprintf("%s[%v]", path, key.Interface()), report)
+		}
+	}
+}
+
+// selfValidatable 返回 val 上的 Validate() 方法，同时支持值接收者和指针接收者
+func selfValidatable(val reflect.Value) (func(ctx context.Context) error, bool) {
+	if !val.CanInterface() {
+		return nil, false
+	}
+	if val.CanAddr() {
+		return validateFunc(val.Addr().Interface())
+	}
+	if fn, ok := validateFunc(val.Interface()); ok {
+		return fn, true
+	}
+	// 不可寻址的值 (如 map 元素) 复制一份后调用指针接收者方法
+	ptrType := reflect.PointerTo(val.Type())
+	if ptrType.Implements(selfValidatableType) || ptrType.Implements(contextValidatableType) {
+		ptr := reflect.New(val.Type())
+		ptr.Elem().Set(val)
+		return validateFunc(ptr.Interface())
+	}
+	return nil, false
+}
+
+// validateFunc 将 SelfValidatable 和 ContextValidatable 统一为带 ctx 的函数
+func validateFunc(i any) (func(ctx context.Context) error, bool) {
+	switch sv := i.(type) {
+	case SelfValidatable:
+		return func(context.Context) error { return sv.Validate() }, true
+	case ContextValidatable:
+		return sv.Validate, true
+	}
+	return nil, false
+}
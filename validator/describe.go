@@ -0,0 +1,61 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Describe 将单条验证规则翻译为可读文本，用于生成配置文档
+//
+// kind 是字段的基础类型，决定 min/max/len 等规则按长度、数值还是元素个数描述。
+// 未设置语言或翻译缺失时返回 "tag=param"。
+func (v *Validator) Describe(field, tag, param string, kind reflect.Kind) string {
+	raw := tag
+	if param != "" {
+		raw = tag + "=" + param
+	}
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	if v.trans == nil {
+		return raw
+	}
+
+	// 带长度/数值语义的规则按类型使用不同的翻译键 (与 go-playground 默认翻译一致)
+	suffix, unit := "number", ""
+	switch kind {
+	case reflect.String:
+		suffix, unit = "string", "character"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		suffix, unit = "items", "item"
+	}
+	if msg, err := v.trans.T(tag+"-"+suffix, field, v.describeParam(tag+"-"+suffix+"-"+unit, unit, param)); err == nil && msg != "" {
+		return msg
+	}
+
+	if msg, err := v.trans.T(tag, field, param); err == nil && msg != "" {
+		return msg
+	}
+	return raw
+}
+
+// describeParam 格式化规则参数，长度和元素个数带上复数单位 (如 "8 characters")
+func (v *Validator) describeParam(key, unit, param string) string {
+	f, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return param
+	}
+	digits := uint64(0)
+	if i := strings.IndexByte(param, '.'); i >= 0 {
+		digits = uint64(len(param) - i - 1)
+	}
+	num := v.trans.FmtNumber(f, digits)
+	if unit == "" {
+		return num
+	}
+	if c, err := v.trans.C(key, f, digits, num); err == nil {
+		return c
+	}
+	return fmt.Sprintf("%s %s", num, unit)
+}
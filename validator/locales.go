@@ -0,0 +1,178 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/de"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/es"
+	"github.com/go-playground/locales/fr"
+	"github.com/go-playground/locales/ja"
+	"github.com/go-playground/locales/ko"
+	"github.com/go-playground/locales/pt"
+	"github.com/go-playground/locales/zh"
+	"github.com/go-playground/locales/zh_Hant_TW"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	de_translations "github.com/go-playground/validator/v10/translations/de"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	es_translations "github.com/go-playground/validator/v10/translations/es"
+	fr_translations "github.com/go-playground/validator/v10/translations/fr"
+	ja_translations "github.com/go-playground/validator/v10/translations/ja"
+	ko_translations "github.com/go-playground/validator/v10/translations/ko"
+	pt_translations "github.com/go-playground/validator/v10/translations/pt"
+	zh_translations "github.com/go-playground/validator/v10/translations/zh"
+	zh_tw_translations "github.com/go-playground/validator/v10/translations/zh_tw"
+)
+
+// ErrUnknownLocale 表示请求的语言没有注册对应的语言包
+var ErrUnknownLocale = errors.New("unknown locale")
+
+// LocalePack 是一种语言的翻译资源
+type LocalePack struct {
+	// Translator 提供复数规则和数字格式 (github.com/go-playground/locales 下的各语言包)
+	Translator locales.Translator
+	// Register 注册内置规则的错误信息，通常为 go-playground translations 包的 RegisterDefaultTranslations
+	Register func(v *validator.Validate, trans ut.Translator) error
+}
+
+var (
+	localesMu sync.RWMutex
+	localeMap = map[string]LocalePack{
+		"zh":    {Translator: zh.New(), Register: zh_translations.RegisterDefaultTranslations},
+		"zh_tw": {Translator: zh_Hant_TW.New(), Register: zh_tw_translations.RegisterDefaultTranslations},
+		"en":    {Translator: en.New(), Register: en_translations.RegisterDefaultTranslations},
+		"ja":    {Translator: ja.New(), Register: ja_translations.RegisterDefaultTranslations},
+		"ko":    {Translator: ko.New(), Register: ko_translations.RegisterDefaultTranslations},
+		"de":    {Translator: de.New(), Register: de_translations.RegisterDefaultTranslations},
+		"fr":    {Translator: fr.New(), Register: fr_translations.RegisterDefaultTranslations},
+		"es":    {Translator: es.New(), Register: es_translations.RegisterDefaultTranslations},
+		"pt":    {Translator: pt.New(), Register: pt_translations.RegisterDefaultTranslations},
+	}
+
+	// localeAliases 将常见的语言标签写法映射到已注册的语言
+	localeAliases = map[string]string{
+		"zh_cn":      "zh",
+		"zh_hans":    "zh",
+		"zh_hans_cn": "zh",
+		"zh_hant":    "zh_tw",
+		"zh_hant_tw": "zh_tw",
+		"zh_hk":      "zh_tw",
+	}
+)
+
+// RegisterLocale 注册 (或替换) 一种语言，之后即可通过 New(name) 使用
+func RegisterLocale(name string, pack LocalePack) error {
+	if pack.Translator == nil || pack.Register == nil {
+		return fmt.Errorf("register locale %q: Translator and Register are required", name)
+	}
+
+	localesMu.Lock()
+	defer localesMu.Unlock()
+	localeMap[NormalizeLocale(name)] = pack
+	return nil
+}
+
+// Locales 返回所有已注册的语言
+func Locales() []string {
+	localesMu.RLock()
+	defer localesMu.RUnlock()
+
+	names := make([]string, 0, len(localeMap))
+	for name := range localeMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NormalizeLocale 统一语言标签的写法: 小写，"-" 替换为 "_"，并解析常见别名
+// 如 "zh-TW" -> "zh_tw"，"zh-Hant" -> "zh_tw"，"zh-CN" -> "zh"
+func NormalizeLocale(name string) string {
+	name = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "-", "_")
+	if alias, ok := localeAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// lookupLocale 查找语言包，返回规范化后的语言名
+func lookupLocale(name string) (string, LocalePack, bool) {
+	localesMu.RLock()
+	defer localesMu.RUnlock()
+
+	name = NormalizeLocale(name)
+	pack, ok := localeMap[name]
+	return name, pack, ok
+}
+
+// matchLocale 查找已注册的语言，带地区的标签 (如 "pt_br") 未注册时退回到基础语言
+func matchLocale(name string) (string, LocalePack, bool) {
+	lang, pack, ok := lookupLocale(name)
+	if !ok {
+		if i := strings.IndexByte(lang, '_'); i >= 0 {
+			return lookupLocale(lang[:i])
+		}
+	}
+	return lang, pack, ok
+}
+
+// MatchLocale 按顺序返回 tags 中第一个已注册的语言 (规范化后的名称)，都未注册时返回 ""
+// 通常与 ParseAcceptLanguage 配合使用
+func MatchLocale(tags ...string) string {
+	for _, tag := range tags {
+		if lang, _, ok := matchLocale(tag); ok {
+			return lang
+		}
+	}
+	return ""
+}
+
+// translator 返回 locale 对应的翻译器，首次使用时加载；locale 为空或未注册时返回默认语言
+func (v *Validator) translator(locale string) (ut.Translator, string, error) {
+	if locale == "" {
+		return v.trans, v.locale, nil
+	}
+	lang, pack, ok := matchLocale(locale)
+	if !ok {
+		return v.trans, v.locale, nil
+	}
+
+	v.mu.RLock()
+	trans, ok := v.translators[lang]
+	v.mu.RUnlock()
+	if ok {
+		return trans, lang, nil
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if trans, ok := v.translators[lang]; ok {
+		return trans, lang, nil
+	}
+	trans, err := v.loadLocale(lang, pack)
+	if err != nil {
+		return nil, "", err
+	}
+	return trans, lang, nil
+}
+
+// loadLocale 在底层验证器上注册语言包及已有的自定义规则模板，调用方需持有写锁 (或处于构造阶段)
+func (v *Validator) loadLocale(lang string, pack LocalePack) (ut.Translator, error) {
+	trans := ut.New(pack.Translator).GetFallback()
+	if err := pack.Register(v.validate, trans); err != nil {
+		return nil, fmt.Errorf("load locale %q: %w", lang, err)
+	}
+	for tag, translations := range v.translations {
+		if err := v.registerTranslationFor(tag, trans, lang, translations); err != nil {
+			return nil, err
+		}
+	}
+	v.translators[lang] = trans
+	return trans, nil
+}
@@ -0,0 +1,99 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// redactedValue 是脱敏字段在 {value} 和 FieldError.Value 中的占位文本
+const redactedValue = "******"
+
+// customMessage 返回字段 msg 标签定义的错误信息，按 msg_<locale>、msg_<语言>、msg 的顺序查找
+//
+// 支持的占位符: {field} 键路径，{param} 规则参数，{value} 字段值。
+func customMessage(field reflect.StructField, e validator.FieldError, path string, value any, locale string) (string, bool) {
+	var keys []string
+	if locale != "" {
+		keys = append(keys, "msg_"+locale)
+		if i := strings.IndexByte(locale, '_'); i >= 0 {
+			keys = append(keys, "msg_"+locale[:i])
+		}
+	}
+	keys = append(keys, "msg")
+
+	for _, key := range keys {
+		if tmpl, ok := field.Tag.Lookup(key); ok && tmpl != "" {
+			return strings.NewReplacer(
+				"{field}", path,
+				"{param}", e.Param(),
+				"{value}", fmt.Sprint(value),
+			).Replace(tmpl), true
+		}
+	}
+	return "", false
+}
+
+// errorPath 返回错误的键路径: 去掉根结构体名，并跳过 squash 嵌入的结构体，如 "Config.Base.port" -> "port"
+func errorPath(root reflect.Type, e validator.FieldError) string {
+	namespace := e.Namespace()
+	i := strings.Index(namespace, ".")
+	if i == -1 {
+		return namespace
+	}
+
+	names := strings.Split(namespace[i+1:], ".")
+	parts := strings.Split(e.StructNamespace(), ".")
+	if len(parts) != len(names)+1 {
+		return namespace[i+1:]
+	}
+
+	path := make([]string, 0, len(names))
+	for j, name := range names {
+		if field, ok := structField(root, strings.Join(parts[:j+2], ".")); ok && isSquash(field) {
+			continue
+		}
+		path = append(path, name)
+	}
+	return strings.Join(path, ".")
+}
+
+// structField 按 go-playground 的 StructNamespace (如 "Config.DB.Hosts[0]") 查找字段定义
+func structField(root reflect.Type, namespace string) (reflect.StructField, bool) {
+	parts := strings.Split(namespace, ".")
+	if len(parts) < 2 {
+		return reflect.StructField{}, false
+	}
+
+	typ := root
+	var field reflect.StructField
+	for _, part := range parts[1:] {
+		typ = indirect(typ)
+		if typ.Kind() != reflect.Struct {
+			return reflect.StructField{}, false
+		}
+
+		// 去掉 dive 产生的下标，如 "Hosts[0]" -> "Hosts"
+		name, _, _ := strings.Cut(part, "[")
+		f, ok := typ.FieldByName(name)
+		if !ok {
+			return reflect.StructField{}, false
+		}
+		field = f
+		typ = f.Type
+		for i := 0; i < strings.Count(part, "["); i++ {
+			typ = indirect(typ).Elem()
+		}
+	}
+	return field, true
+}
+
+// indirect 剥离指针
+func indirect(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ
+}
@@ -0,0 +1,114 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldLevel 提供自定义规则所需的字段信息 (值、参数、父结构体等)
+type FieldLevel = validator.FieldLevel
+
+// StructLevel 提供结构体级规则所需的信息，通过 ReportError 报告字段错误
+type StructLevel = validator.StructLevel
+
+// RuleFunc 是字段级自定义规则，返回 false 表示验证失败
+type RuleFunc func(fl FieldLevel) bool
+
+// RuleCtxFunc 是需要 context 的字段级自定义规则，ctx 来自 ValidateCtx (其余入口为 context.Background())
+type RuleCtxFunc func(ctx context.Context, fl FieldLevel) bool
+
+// StructRuleFunc 是结构体级自定义规则，用于跨字段校验
+type StructRuleFunc func(sl StructLevel)
+
+// RegisterRule 注册自定义验证规则，之后即可在 validate 标签中使用 tag (如 "port"、"duration_min=1s")
+//
+// translations 是按语言区分的错误信息模板，{0} 为字段名，{1} 为规则参数，例如:
+//
+//	v.RegisterRule("port", isPort, map[string]string{
+//		"zh": "{0}必须是有效的端口号",
+//		"en": "{0} must be a valid port",
+//	})
+//
+// 当前语言没有对应模板时回退到 "en"，两者都没有时错误信息为 "tag=param"。
+func (v *Validator) RegisterRule(tag string, fn RuleFunc, translations map[string]string) error {
+	return v.RegisterRuleCtx(tag, func(_ context.Context, fl FieldLevel) bool {
+		return fn(fl)
+	}, translations)
+}
+
+// RegisterRuleCtx 与 RegisterRule 相同，规则可以读取调用方的 ctx (如查询 DNS 时遵守超时)
+// ctx 取消或超时导致的失败由 ValidateCtx 报告为 ctx.Err()，不会产生该规则的错误信息
+func (v *Validator) RegisterRuleCtx(tag string, fn RuleCtxFunc, translations map[string]string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if err := v.validate.RegisterValidationCtx(tag, func(ctx context.Context, fl validator.FieldLevel) bool {
+		return fn(ctx, fl)
+	}); err != nil {
+		return fmt.Errorf("register rule %q: %w", tag, err)
+	}
+	return v.registerTranslation(tag, translations)
+}
+
+// RegisterStructRule 为 types 注册结构体级验证规则
+// 规则内通过 sl.ReportError 报告的 tag 可以用 RegisterTranslation 提供错误信息
+func (v *Validator) RegisterStructRule(fn StructRuleFunc, types ...any) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.validate.RegisterStructValidation(func(sl validator.StructLevel) {
+		fn(sl)
+	}, types...)
+}
+
+// RegisterTranslation 为 tag 注册 (或覆盖) 按语言区分的错误信息模板，规则同 RegisterRule
+func (v *Validator) RegisterTranslation(tag string, translations map[string]string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.registerTranslation(tag, translations)
+}
+
+// registerTranslation 记录模板并为所有已加载的语言注册，调用方需持有写锁
+func (v *Validator) registerTranslation(tag string, translations map[string]string) error {
+	v.translations[tag] = translations
+	for lang, trans := range v.translators {
+		if err := v.registerTranslationFor(tag, trans, lang, translations); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *Validator) registerTranslationFor(tag string, trans ut.Translator, locale string, translations map[string]string) error {
+	text, ok := translations[locale]
+	if !ok {
+		text, ok = translations["en"]
+	}
+	if !ok {
+		// 没有可用模板时同样注册，避免 go-playground 输出未翻译的原始错误
+		text = ""
+	}
+
+	err := v.validate.RegisterTranslation(tag, trans,
+		func(trans ut.Translator) error {
+			if text == "" {
+				return nil
+			}
+			return trans.Add(tag, text, true)
+		},
+		func(trans ut.Translator, fe validator.FieldError) string {
+			if msg, err := trans.T(tag, fe.Field(), fe.Param()); err == nil && text != "" {
+				return msg
+			}
+			return strings.TrimSuffix(fe.Tag()+"="+fe.Param(), "=")
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("register translation %q: %w", tag, err)
+	}
+	return nil
+}
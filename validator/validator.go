@@ -1,160 +1,336 @@
 package validator
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 
-	"github.com/go-playground/locales/en"
-	"github.com/go-playground/locales/zh"
 	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
-	en_translations "github.com/go-playground/validator/v10/translations/en"
-	zh_translations "github.com/go-playground/validator/v10/translations/zh"
 )
 
+// fieldName 返回字段在错误信息中的名称
+// 统一逻辑：mapstructure > yaml > json > toml > FieldName
+func fieldName(fld reflect.StructField) string {
+	// Priority 1: mapstructure
+	if tag := fld.Tag.Get("mapstructure"); tag != "" {
+		name := strings.SplitN(tag, ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+
+	// Priority 2: yaml
+	if tag := fld.Tag.Get("yaml"); tag != "" {
+		name := strings.SplitN(tag, ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+
+	// Priority 3: json
+	if tag := fld.Tag.Get("json"); tag != "" {
+		name := strings.SplitN(tag, ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+
+	// Priority 4: toml
+	if tag := fld.Tag.Get("toml"); tag != "" {
+		name := strings.SplitN(tag, ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+
+	return fld.Name
+}
+
+// isSquash 判断字段是否以 ",squash" 嵌入 (与 mapstructure 一致，只看第一个非空的标签)
+// squash 嵌入的结构体与 mapstructure 解码时一样不占键路径段
+func isSquash(fld reflect.StructField) bool {
+	if indirect(fld.Type).Kind() != reflect.Struct {
+		return false
+	}
+	for _, name := range []string{"mapstructure", "yaml", "json", "toml"} {
+		if tag := fld.Tag.Get(name); tag != "" {
+			return slices.Contains(strings.Split(tag, ",")[1:], "squash")
+		}
+	}
+	return false
+}
+
 // SelfValidatable 定义了自验证接口
 type SelfValidatable interface {
 	Validate() error
 }
 
+// ContextValidatable 是需要 context 的自验证接口 (如查询 DNS、访问注册中心)
+// 通过 ValidateCtx 调用时传入调用方的 ctx，通过 Validate 调用时传入 context.Background()
+type ContextValidatable interface {
+	Validate(ctx context.Context) error
+}
+
 // Validator 封装结构体
 type Validator struct {
+	mu       sync.RWMutex // 注册规则和加载语言时独占，验证时共享 (go-playground 的注册不是并发安全的)
 	validate *validator.Validate
 	trans    ut.Translator
+	locale   string // 默认语言，未开启翻译时为空
+
+	translators  map[string]ut.Translator     // 已加载的语言 (含默认语言)，供 ValidateLocale 复用
+	translations map[string]map[string]string // 自定义规则的错误信息模板，加载新语言时补注册
+
+	redact func(field reflect.StructField) bool // RegisterRedactor 注册的敏感字段判断，nil 表示不脱敏
 }
 
 // New 初始化验证器
+//
+// locale 为错误信息语言 (如 "zh"、"en"、"ja"、"zh-TW")，不传或传入 "" 表示不翻译 (性能最高)。
+// 多个参数依次尝试，后面的参数作为显式回退，如 New("pt-BR", "pt", "en")；
+// 回退链中的 "" 表示退回到不翻译。所有语言都未注册时返回 ErrUnknownLocale。
 func New(locale ...string) (*Validator, error) {
-	v := validator.New()
+	v := &Validator{
+		validate:     validator.New(),
+		translators:  make(map[string]ut.Translator),
+		translations: make(map[string]map[string]string),
+	}
 
 	// 1. 注册自定义 Tag Name 获取函数
 	// 统一逻辑：mapstructure > yaml > json > toml > FieldName
-	v.RegisterTagNameFunc(func(fld reflect.StructField) string {
-		// Priority 1: mapstructure
-		if tag := fld.Tag.Get("mapstructure"); tag != "" {
-			name := strings.SplitN(tag, ",", 2)[0]
-			if name == "-" {
-				return ""
-			}
-			if name != "" {
-				return name
-			}
-		}
+	v.validate.RegisterTagNameFunc(fieldName)
 
-		// Priority 2: yaml
-		if tag := fld.Tag.Get("yaml"); tag != "" {
-			name := strings.SplitN(tag, ",", 2)[0]
-			if name == "-" {
-				return ""
-			}
-			if name != "" {
-				return name
-			}
-		}
+	// 2. 语言包处理
+	if len(locale) == 0 {
+		return v, nil
+	}
 
-		// Priority 3: json
-		if tag := fld.Tag.Get("json"); tag != "" {
-			name := strings.SplitN(tag, ",", 2)[0]
-			if name == "-" {
-				return ""
-			}
-			if name != "" {
-				return name
-			}
+	for _, name := range locale {
+		if name == "" {
+			return v, nil
+		}
+		lang, pack, ok := lookupLocale(name)
+		if !ok {
+			continue
 		}
 
-		// Priority 4: toml
-		if tag := fld.Tag.Get("toml"); tag != "" {
-			name := strings.SplitN(tag, ",", 2)[0]
-			if name == "-" {
-				return ""
-			}
-			if name != "" {
-				return name
-			}
+		trans, err := v.loadLocale(lang, pack)
+		if err != nil {
+			return nil, err
 		}
+		v.trans, v.locale = trans, lang
+		return v, nil
+	}
+	return nil, fmt.Errorf("%w: %s (available: %s)", ErrUnknownLocale, strings.Join(locale, ", "), strings.Join(Locales(), ", "))
+}
 
-		return fld.Name
-	})
+// RegisterCustomTypeFunc 注册自定义类型取值函数，验证时以 fn 的返回值代替字段原值
+// 适用于包装类型 (如 conf.Secret)，使 validate 标签作用于其内部值
+func (v *Validator) RegisterCustomTypeFunc(fn func(field reflect.Value) any, types ...any) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.validate.RegisterCustomTypeFunc(fn, types...)
+}
 
-	// 2. 语言包处理 (保持不变)
-	if len(locale) == 0 || locale[0] == "" {
-		return &Validator{validate: v, trans: nil}, nil
-	}
+// RegisterRedactor 注册敏感字段判断函数，fn 返回 true 的字段在 msg 的 {value} 和 FieldError.Value 中输出为 "******"
+// 再次调用会替换之前注册的函数；只需对单次验证脱敏时使用 ContextWithRedactor
+func (v *Validator) RegisterRedactor(fn func(field reflect.StructField) bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.redact = fn
+}
 
-	lang := locale[0]
-	zhT := zh.New()
-	enT := en.New()
-	uni := ut.New(enT, zhT, enT)
+// FieldError 是单个字段的验证错误
+type FieldError struct {
+	Path    string `json:"path"`            // 点分隔的键路径，如 "db.port"
+	Tag     string `json:"tag"`             // 失败的规则，如 "min"
+	Param   string `json:"param,omitempty"` // 规则参数，如 "1024"
+	Value   any    `json:"value,omitempty"` // 字段的实际值 (conf 会对敏感字段脱敏)
+	Message string `json:"message"`         // 翻译后的错误信息
 
-	trans, ok := uni.GetTranslator(lang)
-	if !ok {
-		// 找不到语言时，默认回退到英文，避免报错
-		trans, _ = uni.GetTranslator("en")
-	}
+	// 字段值所在的配置文件位置，由 conf 在加载时填充，未知时为空
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+}
 
-	var err error
-	switch lang {
-	case "zh":
-		err = zh_translations.RegisterDefaultTranslations(v, trans)
-	default:
-		err = en_translations.RegisterDefaultTranslations(v, trans)
+// Location 返回 "file:line:col"，位置未知时为空
+func (f FieldError) Location() string {
+	if f.File == "" {
+		return ""
 	}
-	if err != nil {
-		return nil, err
-	}
-
-	return &Validator{validate: v, trans: trans}, nil
+	return fmt.Sprintf("%s:%d:%d", f.File, f.Line, f.Column)
 }
 
+// ValidationError 包含所有未通过验证的字段
 type ValidationError struct {
-	Errors map[string]string
+	Errors map[string]string // 键路径 -> 错误信息，保留以兼容旧代码
+	Fields []FieldError      // 逐字段的结构化错误，按结构体字段声明顺序排列
+}
+
+// fieldList 返回有序的字段错误；只设置了 Errors 时按键路径排序生成
+func (e *ValidationError) fieldList() []FieldError {
+	if len(e.Fields) > 0 || len(e.Errors) == 0 {
+		return e.Fields
+	}
+
+	paths := make([]string, 0, len(e.Errors))
+	for path := range e.Errors {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	fields := make([]FieldError, len(paths))
+	for i, path := range paths {
+		fields[i] = FieldError{Path: path, Message: e.Errors[path]}
+	}
+	return fields
 }
 
 func (e *ValidationError) Error() string {
 	var msgs []string
-	for field, msg := range e.Errors {
-		msgs = append(msgs, fmt.Sprintf("%s: %s", field, msg))
+	for _, f := range e.fieldList() {
+		msg := f.Message
+		if f.Path != "" {
+			msg = fmt.Sprintf("%s: %s", f.Path, f.Message)
+		}
+		if loc := f.Location(); loc != "" {
+			msg = loc + ": " + msg
+		}
+		msgs = append(msgs, msg)
 	}
 	return fmt.Sprintf("validation failed:\n - %s", strings.Join(msgs, "\n - "))
 }
 
+// MarshalJSON 输出 {"message": "validation failed", "errors": [...]}，便于 HTTP 接口直接返回 400 响应体
+func (e *ValidationError) MarshalJSON() ([]byte, error) {
+	fields := e.fieldList()
+	if fields == nil {
+		fields = []FieldError{}
+	}
+	return json.Marshal(struct {
+		Message string       `json:"message"`
+		Errors  []FieldError `json:"errors"`
+	}{Message: "validation failed", Errors: fields})
+}
+
 // Validate 执行验证 (保持不变)
 func (v *Validator) Validate(i interface{}) error {
 	if sv, ok := i.(SelfValidatable); ok {
 		return sv.Validate()
 	}
+	return v.validateWith(context.Background(), i, "")
+}
+
+// ValidateLocale 与 Validate 相同，但错误信息使用 locale 指定的语言
+//
+// 语言在首次使用时加载并缓存，所有语言共享同一个底层验证器及其结构体元数据缓存，
+// 多语言 API 只需一个 Validator。locale 为空或未注册时使用 New 指定的默认语言，
+// "pt-BR" 等带地区的标签在未注册时退回到 "pt"。
+func (v *Validator) ValidateLocale(i interface{}, locale string) error {
+	return v.validateWith(context.Background(), i, locale)
+}
+
+// validateWith 依次尝试 SelfValidatable、ContextValidatable，否则执行标签验证
+func (v *Validator) validateWith(ctx context.Context, i interface{}, locale string) error {
+	switch sv := i.(type) {
+	case SelfValidatable:
+		return sv.Validate()
+	case ContextValidatable:
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return sv.Validate(ctx)
+	}
 
-	err := v.validate.Struct(i)
+	trans, lang, err := v.translator(locale)
+	if err != nil {
+		return err
+	}
+	return v.validateTags(ctx, i, trans, lang)
+}
+
+// validateTags 执行 validate 标签验证，并按 trans 将错误翻译为 *ValidationError
+// ctx 传给 RegisterRuleCtx 注册的规则；ctx 已取消或超时时返回 ctx.Err()，而不是可能由此导致的规则失败
+func (v *Validator) validateTags(ctx context.Context, i interface{}, trans ut.Translator, locale string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	err := v.validate.StructCtx(ctx, i)
 	if err == nil {
 		return nil
 	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
 
 	if _, ok := err.(*validator.InvalidValidationError); ok {
 		return fmt.Errorf("invalid validation error: %w", err)
 	}
 
 	validationErrors := err.(validator.ValidationErrors)
+	root := reflect.TypeOf(i)
+	ctxRedact := redactorFromContext(ctx)
 	translatedErrors := make(map[string]string)
+	fields := make([]FieldError, 0, len(validationErrors))
 
+	// go-playground 按字段声明顺序深度优先遍历，错误顺序即结构体顺序
 	for _, e := range validationErrors {
-		// 处理 Namespace (去除结构体前缀)
-		namespace := e.Namespace()
-		if i := strings.Index(namespace, "."); i != -1 {
-			namespace = namespace[i+1:]
+		// 处理 Namespace (去除结构体前缀和 squash 嵌入的结构体)
+		namespace := errorPath(root, e)
+
+		value := e.Value()
+		field, found := structField(root, e.StructNamespace())
+		if found && (v.redact != nil && v.redact(field) || ctxRedact != nil && ctxRedact(field)) {
+			value = redactedValue
 		}
 
-		if v.trans != nil {
-			translatedErrors[namespace] = e.Translate(v.trans)
-		} else {
-			if e.Param() != "" {
-				translatedErrors[namespace] = fmt.Sprintf("%s=%s", e.Tag(), e.Param())
+		// msg 标签优先于通用翻译
+		var msg string
+		var ok bool
+		if found {
+			msg, ok = customMessage(field, e, namespace, value, locale)
+		}
+		if !ok {
+			if trans != nil {
+				msg = e.Translate(trans)
+			} else if e.Param() != "" {
+				msg = fmt.Sprintf("%s=%s", e.Tag(), e.Param())
 			} else {
-				translatedErrors[namespace] = e.Tag()
+				msg = e.Tag()
 			}
 		}
+		translatedErrors[namespace] = msg
+		fields = append(fields, FieldError{
+			Path:    namespace,
+			Tag:     e.Tag(),
+			Param:   e.Param(),
+			Value:   value,
+			Message: msg,
+		})
 	}
 
-	return &ValidationError{Errors: translatedErrors}
+	return &ValidationError{Errors: translatedErrors, Fields: fields}
 }
@@ -1,8 +1,19 @@
 package validator
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/go-playground/locales/en"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
 )
 
 // 定义一个用于测试的结构体
@@ -47,9 +58,23 @@ func TestNew(t *testing.T) {
 	})
 
 	t.Run("Should return error for unsupported locale", func(t *testing.T) {
-		_, err := New("fr")
-		if err == nil {
-			t.Log("Warning: 'fr' locale might have fallen back to default or no error returned")
+		_, err := New("xx")
+		if !errors.Is(err, ErrUnknownLocale) {
+			t.Errorf("Expected ErrUnknownLocale, got %v", err)
+		}
+	})
+
+	t.Run("Should use explicit fallback", func(t *testing.T) {
+		v, err := New("pt-BR", "xx", "pt")
+		if err != nil {
+			t.Fatalf("Expected fallback to pt, got %v", err)
+		}
+		if v.locale != "pt" {
+			t.Errorf("Expected locale pt, got %q", v.locale)
+		}
+
+		if v, err := New("xx", ""); err != nil || v.trans != nil {
+			t.Errorf("Expected fallback to untranslated validator, got %v", err)
 		}
 	})
 }
@@ -200,3 +225,627 @@ func TestValidationError_Error_String(t *testing.T) {
 		t.Error("Error string should contain field error")
 	}
 }
+
+func TestValidationError_FieldsOrdered(t *testing.T) {
+	v, _ := New("en")
+	cfg := UserConfig{Age: 10, Settings: Settings{Theme: "blue"}}
+
+	err := v.Validate(&cfg)
+	ve, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Expected *ValidationError, got %T", err)
+	}
+
+	// 按结构体字段声明顺序排列
+	var paths []string
+	for _, f := range ve.Fields {
+		paths = append(paths, f.Path)
+	}
+	want := "user_name,email_addr,role_name,Age,Ignored,settings.theme_mode"
+	if got := strings.Join(paths, ","); got != want {
+		t.Errorf("Expected field order %s, got %s", want, got)
+	}
+
+	age := ve.Fields[3]
+	if age.Tag != "gte" || age.Param != "18" || age.Value != 10 || age.Message != ve.Errors["Age"] {
+		t.Errorf("Expected structured gte error, got %+v", age)
+	}
+
+	// 多次调用输出一致
+	if ve.Error() != ve.Error() || !strings.Contains(ve.Error(), " - user_name: ") {
+		t.Errorf("Expected deterministic error string, got %s", ve.Error())
+	}
+}
+
+func TestValidationError_MapOnlyDeterministic(t *testing.T) {
+	ve := &ValidationError{Errors: map[string]string{"b": "2", "a": "1", "c": "3"}}
+	want := "validation failed:\n - a: 1\n - b: 2\n - c: 3"
+	for i := 0; i < 5; i++ {
+		if got := ve.Error(); got != want {
+			t.Fatalf("Expected %q, got %q", want, got)
+		}
+	}
+}
+
+func TestValidationError_MarshalJSON(t *testing.T) {
+	v, _ := New("en")
+	err := v.Validate(&Settings{Theme: "blue"})
+
+	data, jerr := json.Marshal(err)
+	if jerr != nil {
+		t.Fatalf("Expected no error, got %v", jerr)
+	}
+
+	var body struct {
+		Message string `json:"message"`
+		Errors  []struct {
+			Path, Tag, Param, Message string
+			Value                     any
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		t.Fatalf("Expected valid JSON, got %s", data)
+	}
+	if body.Message != "validation failed" || len(body.Errors) != 1 {
+		t.Fatalf("Expected one error, got %s", data)
+	}
+	e := body.Errors[0]
+	if e.Path != "theme_mode" || e.Tag != "oneof" || e.Param != "dark light" || e.Value != "blue" || e.Message == "" {
+		t.Errorf("Expected structured oneof error, got %s", data)
+	}
+}
+
+type TLSConfig struct {
+	Cert string `mapstructure:"cert"`
+	Key  string `mapstructure:"key"`
+}
+
+func (t TLSConfig) Validate() error {
+	if (t.Cert == "") != (t.Key == "") {
+		return fmt.Errorf("cert and key must be set together")
+	}
+	return nil
+}
+
+type Backend struct {
+	URL string `mapstructure:"url"`
+}
+
+func (b *Backend) Validate() error {
+	if b.URL == "" {
+		return fmt.Errorf("url is required")
+	}
+	return nil
+}
+
+type DeepConfig struct {
+	Name     string             `mapstructure:"name" validate:"required"`
+	TLS      *TLSConfig         `mapstructure:"tls"`
+	Backends []Backend          `mapstructure:"backends"`
+	Named    map[string]Backend `mapstructure:"named"`
+}
+
+func (c *DeepConfig) Validate() error {
+	if c.Name == "forbidden" {
+		return fmt.Errorf("name is reserved")
+	}
+	return nil
+}
+
+func TestValidator_ValidateDeep(t *testing.T) {
+	v, _ := New("en")
+	cfg := &DeepConfig{
+		Name:     "forbidden",
+		TLS:      &TLSConfig{Cert: "cert.pem"},
+		Backends: []Backend{{URL: "http://a"}, {}},
+		Named:    map[string]Backend{"b": {}, "a": {URL: "http://a"}},
+	}
+
+	err := v.ValidateDeep(cfg)
+	ve, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Expected *ValidationError, got %v", err)
+	}
+
+	want := []FieldError{
+		{Path: "", Tag: SelfTag, Message: "name is reserved"},
+		{Path: "tls", Tag: SelfTag, Message: "cert and key must be set together"},
+		{Path: "backends[1]", Tag: SelfTag, Message: "url is required"},
+		{Path: "named[b]", Tag: SelfTag, Message: "url is required"},
+	}
+	if len(ve.Fields) != len(want) {
+		t.Fatalf("Expected %d errors, got %+v", len(want), ve.Fields)
+	}
+	for i, w := range want {
+		if ve.Fields[i] != w {
+			t.Errorf("Expected %+v, got %+v", w, ve.Fields[i])
+		}
+	}
+
+	t.Run("Tags Still Run", func(t *testing.T) {
+		// 默认模式下根类型实现 SelfValidatable 时会跳过标签验证
+		if err := v.Validate(&DeepConfig{}); err != nil {
+			t.Errorf("Expected fast path to skip tags, got %v", err)
+		}
+
+		err := v.ValidateDeep(&DeepConfig{})
+		ve, ok := err.(*ValidationError)
+		if !ok || ve.Fields[0].Path != "name" || ve.Fields[0].Tag != "required" {
+			t.Errorf("Expected required tag error on name, got %v", err)
+		}
+	})
+
+	t.Run("Nested ValidationError", func(t *testing.T) {
+		type Wrapper struct {
+			Inner *nestedErrConfig `mapstructure:"inner"`
+		}
+		err := v.ValidateDeep(&Wrapper{Inner: &nestedErrConfig{}})
+		ve, ok := err.(*ValidationError)
+		if !ok || len(ve.Fields) != 1 || ve.Fields[0].Path != "inner.port" {
+			t.Errorf("Expected nested error prefixed with inner, got %v", err)
+		}
+	})
+	t.Run("Squashed Embed", func(t *testing.T) {
+		type Base struct {
+			Port int        `mapstructure:"port" validate:"min=1024"`
+			TLS  *TLSConfig `mapstructure:"tls"`
+		}
+		type Squashed struct {
+			Base `mapstructure:",squash"`
+		}
+		err := v.ValidateDeep(&Squashed{Base{Port: 80, TLS: &TLSConfig{Cert: "cert.pem"}}})
+		ve, ok := err.(*ValidationError)
+		if !ok || len(ve.Fields) != 2 {
+			t.Fatalf("Expected two errors, got %v", err)
+		}
+		// squash 嵌入的结构体不占键路径段
+		if ve.Fields[0].Path != "port" || ve.Fields[1].Path != "tls" {
+			t.Errorf("Expected paths [port tls], got %+v", ve.Fields)
+		}
+	})
+}
+
+type nestedErrConfig struct {
+	Port int `mapstructure:"port"`
+}
+
+func (c *nestedErrConfig) Validate() error {
+	return &ValidationError{Errors: map[string]string{"port": "port is required"}}
+}
+
+type RuleConfig struct {
+	Port    int           `mapstructure:"port" validate:"port"`
+	Timeout time.Duration `mapstructure:"timeout" validate:"duration_min=1s"`
+	Min     int           `mapstructure:"min"`
+	Max     int           `mapstructure:"max"`
+}
+
+func registerTestRules(t *testing.T, v *Validator) {
+	err := v.RegisterRule("port", func(fl FieldLevel) bool {
+		p := fl.Field().Int()
+		return p > 0 && p < 65536
+	}, map[string]string{
+		"zh": "{0}必须是有效的端口号",
+		"en": "{0} must be a valid port",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	err = v.RegisterRule("duration_min", func(fl FieldLevel) bool {
+		min, err := time.ParseDuration(fl.Param())
+		return err == nil && time.Duration(fl.Field().Int()) >= min
+	}, map[string]string{"en": "{0} must be at least {1}"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	v.RegisterStructRule(func(sl StructLevel) {
+		c := sl.Current().Interface().(RuleConfig)
+		if c.Min > c.Max {
+			sl.ReportError(c.Min, "min", "Min", "ltefield_max", "")
+		}
+	}, RuleConfig{})
+	if err := v.RegisterTranslation("ltefield_max", map[string]string{"zh": "{0}不能大于max"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestValidator_RegisterRule(t *testing.T) {
+	cfg := &RuleConfig{Port: 70000, Timeout: time.Millisecond, Min: 2, Max: 1}
+
+	t.Run("Chinese", func(t *testing.T) {
+		v, _ := New("zh")
+		registerTestRules(t, v)
+
+		ve, ok := v.Validate(cfg).(*ValidationError)
+		if !ok {
+			t.Fatal("Expected *ValidationError")
+		}
+		if ve.Errors["port"] != "port必须是有效的端口号" {
+			t.Errorf("Expected zh message for port, got %q", ve.Errors["port"])
+		}
+		// 没有 zh 模板时回退到 en
+		if ve.Errors["timeout"] != "timeout must be at least 1s" {
+			t.Errorf("Expected en fallback for duration_min, got %q", ve.Errors["timeout"])
+		}
+		if ve.Errors["min"] != "min不能大于max" {
+			t.Errorf("Expected struct rule message, got %q", ve.Errors["min"])
+		}
+	})
+
+	t.Run("No Locale", func(t *testing.T) {
+		v, _ := New()
+		registerTestRules(t, v)
+
+		ve, ok := v.Validate(cfg).(*ValidationError)
+		if !ok || ve.Errors["port"] != "port" || ve.Errors["timeout"] != "duration_min=1s" {
+			t.Errorf("Expected raw tags without locale, got %v", ve)
+		}
+	})
+
+	t.Run("Missing Translation", func(t *testing.T) {
+		v, _ := New("en")
+		if err := v.RegisterRule("even", func(fl FieldLevel) bool { return fl.Field().Int()%2 == 0 }, nil); err != nil {
+			t.Fatal(err)
+		}
+		type Even struct {
+			N int `mapstructure:"n" validate:"even"`
+		}
+		ve, ok := v.Validate(&Even{N: 1}).(*ValidationError)
+		if !ok || ve.Errors["n"] != "even" {
+			t.Errorf("Expected tag as message without translation, got %v", ve)
+		}
+	})
+}
+
+func TestValidator_BuiltinLocales(t *testing.T) {
+	cases := map[string]string{
+		"ja":    "theme_modeは[dark light]のうちのいずれかでなければなりません",
+		"ko":    "theme_mode",
+		"zh-TW": "theme_mode必須是[dark light]中的一個",
+		"de":    "theme_mode",
+		"fr":    "theme_mode",
+		"es":    "theme_mode",
+		"pt":    "theme_mode",
+	}
+	for locale, want := range cases {
+		v, err := New(locale)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", locale, err)
+		}
+		ve, ok := v.Validate(&Settings{Theme: "blue"}).(*ValidationError)
+		if !ok {
+			t.Fatalf("%s: expected *ValidationError", locale)
+		}
+		msg := ve.Errors["theme_mode"]
+		// 必须是翻译后的信息，而不是 go-playground 的原始英文错误
+		if !strings.Contains(msg, want) || strings.Contains(msg, "Error:Field validation") {
+			t.Errorf("%s: expected translated message containing %q, got %q", locale, want, msg)
+		}
+	}
+}
+
+func TestRegisterLocale(t *testing.T) {
+	err := RegisterLocale("en-pirate", LocalePack{
+		Translator: en.New(),
+		Register: func(v *validator.Validate, trans ut.Translator) error {
+			return v.RegisterTranslation("required", trans,
+				func(ut ut.Translator) error { return ut.Add("required", "{0} be missin', matey", true) },
+				func(ut ut.Translator, fe validator.FieldError) string {
+					msg, _ := ut.T("required", fe.Field())
+					return msg
+				})
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := New("en_PIRATE")
+	if err != nil {
+		t.Fatalf("Expected custom locale, got %v", err)
+	}
+	type Ship struct {
+		Name string `mapstructure:"name" validate:"required"`
+	}
+	ve, _ := v.Validate(&Ship{}).(*ValidationError)
+	if ve == nil || ve.Errors["name"] != "name be missin', matey" {
+		t.Errorf("Expected custom locale message, got %v", ve)
+	}
+
+	if err := RegisterLocale("broken", LocalePack{}); err == nil {
+		t.Error("Expected error for incomplete locale pack")
+	}
+}
+
+func TestValidator_CustomMessage(t *testing.T) {
+	type Upstream struct {
+		Host string `mapstructure:"host" validate:"required" msg:"{field} is required"`
+	}
+	type Ops struct {
+		Port      int        `mapstructure:"port" validate:"min=1024" msg:"{field} must be a non-privileged port (>= {param}), got {value}" msg_zh:"{field} 必须是非特权端口 (>= {param})，当前为 {value}"`
+		Mode      string     `mapstructure:"mode" validate:"oneof=a b" msg_en:"unsupported mode {value}"`
+		Token     string     `mapstructure:"token" validate:"len=8" secret:"true" msg:"bad token {value}"`
+		Upstreams []Upstream `mapstructure:"upstreams" validate:"dive"`
+		Name      string     `mapstructure:"name" validate:"required"`
+	}
+	cfg := &Ops{Port: 80, Mode: "c", Token: "hunter2", Upstreams: []Upstream{{Host: "a"}, {}}}
+
+	cases := []struct {
+		locale string
+		want   map[string]string
+	}{
+		{"", map[string]string{
+			"port":              "port must be a non-privileged port (>= 1024), got 80",
+			"mode":              "oneof=a b",
+			"token":             "bad token ******",
+			"upstreams[1].host": "upstreams[1].host is required",
+			"name":              "required",
+		}},
+		{"zh", map[string]string{
+			"port": "port 必须是非特权端口 (>= 1024)，当前为 80",
+			"mode": "mode必须是[a b]中的一个",
+		}},
+		// zh_tw 回退到 msg_zh
+		{"zh-TW", map[string]string{
+			"port": "port 必须是非特权端口 (>= 1024)，当前为 80",
+		}},
+		{"en", map[string]string{
+			"port": "port must be a non-privileged port (>= 1024), got 80",
+			"mode": "unsupported mode c",
+			"name": "name is a required field",
+		}},
+	}
+	for _, c := range cases {
+		var v *Validator
+		var err error
+		if c.locale == "" {
+			v, err = New()
+		} else {
+			v, err = New(c.locale)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		v.RegisterRedactor(func(field reflect.StructField) bool { return field.Tag.Get("secret") == "true" })
+		ve, ok := v.Validate(cfg).(*ValidationError)
+		if !ok {
+			t.Fatalf("%q: expected *ValidationError", c.locale)
+		}
+		for path, want := range c.want {
+			if got := ve.Errors[path]; got != want {
+				t.Errorf("%q: %s: expected %q, got %q", c.locale, path, want, got)
+			}
+		}
+		for _, f := range ve.Fields {
+			if f.Path == "token" && f.Value != "******" {
+				t.Errorf("%q: expected redacted token value, got %v", c.locale, f.Value)
+			}
+		}
+	}
+
+	// 未注册脱敏函数时 {value} 输出原值
+	v, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ve, ok := v.Validate(cfg).(*ValidationError)
+	if !ok || ve.Errors["token"] != "bad token hunter2" {
+		t.Errorf("Expected raw token without redactor, got %v", ve)
+	}
+
+	// ContextWithRedactor 只作用于本次验证
+	ctx := ContextWithRedactor(context.Background(), func(field reflect.StructField) bool { return field.Name == "Token" })
+	ve, ok = v.ValidateCtx(ctx, cfg).(*ValidationError)
+	if !ok || ve.Errors["token"] != "bad token ******" {
+		t.Errorf("Expected token redacted via context, got %v", ve)
+	}
+	ve, ok = v.Validate(cfg).(*ValidationError)
+	if !ok || ve.Errors["token"] != "bad token hunter2" {
+		t.Errorf("Expected context redactor not to persist, got %v", ve)
+	}
+}
+
+func TestValidator_ValidateLocale(t *testing.T) {
+	v, err := New("zh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = v.RegisterRule("even", func(fl FieldLevel) bool { return fl.Field().Int()%2 == 0 },
+		map[string]string{"zh": "{0}必须是偶数", "en": "{0} must be even"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type Request struct {
+		Theme string `json:"theme" validate:"oneof=dark light"`
+		Size  int    `json:"size" validate:"even" msg_ja:"{field} は偶数で指定してください"`
+	}
+	req := &Request{Theme: "blue", Size: 3}
+
+	cases := []struct {
+		locale string
+		theme  string
+		size   string
+	}{
+		{"", "theme必须是[dark light]中的一个", "size必须是偶数"},
+		{"en", "theme must be one of [dark light]", "size must be even"},
+		{"ja", "themeは[dark light]のうちのいずれかでなければなりません", "size は偶数で指定してください"},
+		// 未注册的地区退回到基础语言，完全未知的语言使用默认语言
+		{"en-GB", "theme must be one of [dark light]", "size must be even"},
+		{"xx", "theme必须是[dark light]中的一个", "size必须是偶数"},
+	}
+	for _, c := range cases {
+		ve, ok := v.ValidateLocale(req, c.locale).(*ValidationError)
+		if !ok {
+			t.Fatalf("%q: expected *ValidationError", c.locale)
+		}
+		if ve.Errors["theme"] != c.theme || ve.Errors["size"] != c.size {
+			t.Errorf("%q: expected %q / %q, got %v", c.locale, c.theme, c.size, ve.Errors)
+		}
+	}
+
+	// 默认语言不受按请求选择的语言影响
+	if ve, _ := v.Validate(req).(*ValidationError); ve == nil || ve.Errors["size"] != "size必须是偶数" {
+		t.Errorf("Expected default locale to stay zh, got %v", ve)
+	}
+
+	t.Run("Context", func(t *testing.T) {
+		ctx := ContextWithLocale(context.Background(), "en")
+		ve, _ := v.ValidateCtx(ctx, req).(*ValidationError)
+		if ve == nil || ve.Errors["size"] != "size must be even" {
+			t.Errorf("Expected locale from context, got %v", ve)
+		}
+		if LocaleFromContext(context.Background()) != "" {
+			t.Error("Expected empty locale without ContextWithLocale")
+		}
+	})
+
+	t.Run("Untranslated Default", func(t *testing.T) {
+		v, _ := New()
+		settings := &Settings{Theme: "blue"}
+		if ve, _ := v.ValidateLocale(settings, "en").(*ValidationError); ve == nil || ve.Errors["theme_mode"] != "theme_mode must be one of [dark light]" {
+			t.Errorf("Expected en translation on untranslated validator, got %v", ve)
+		}
+		if ve, _ := v.Validate(settings).(*ValidationError); ve == nil || ve.Errors["theme_mode"] != "oneof=dark light" {
+			t.Errorf("Expected default to stay untranslated, got %v", ve)
+		}
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		v, _ := New()
+		locales := []string{"zh", "en", "ja", "ko", "de", "fr", "es", "pt"}
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			for _, locale := range locales {
+				wg.Add(1)
+				go func(locale string) {
+					defer wg.Done()
+					if _, ok := v.ValidateLocale(&Settings{Theme: "blue"}, locale).(*ValidationError); !ok {
+						t.Errorf("%s: expected *ValidationError", locale)
+					}
+				}(locale)
+			}
+		}
+		wg.Wait()
+	})
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	cases := map[string][]string{
+		"":                                      {},
+		"zh-CN":                                 {"zh-CN"},
+		"fr-CH, fr;q=0.9, en;q=0.8, *;q=0.5":    {"fr-CH", "fr", "en"},
+		"en;q=0.5, ja, de;q=0.5, ko;q=0":        {"ja", "en", "de"},
+		" zh-TW ;q=0.8 , pt-BR ; q=1 ,, es;q=x": {"pt-BR", "es", "zh-TW"},
+	}
+	for header, want := range cases {
+		if got := ParseAcceptLanguage(header); fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("%q: expected %v, got %v", header, want, got)
+		}
+	}
+
+	if got := MatchLocale(ParseAcceptLanguage("xx, pt-BR;q=0.9, en;q=0.8")...); got != "pt" {
+		t.Errorf("Expected pt, got %q", got)
+	}
+	if got := MatchLocale("zh-Hant", "en"); got != "zh_tw" {
+		t.Errorf("Expected zh_tw, got %q", got)
+	}
+	if got := MatchLocale("xx"); got != "" {
+		t.Errorf("Expected no match, got %q", got)
+	}
+}
+
+type userKey struct{}
+
+// Account 通过 ctx 检查用户名是否已被占用
+type Account struct {
+	Name string `json:"name" validate:"required"`
+}
+
+func (a *Account) Validate(ctx context.Context) error {
+	if taken, _ := ctx.Value(userKey{}).(string); taken == a.Name {
+		return fmt.Errorf("name %q is taken", a.Name)
+	}
+	return nil
+}
+
+type Team struct {
+	Owner   Account   `json:"owner"`
+	Members []Account `json:"members" validate:"dive"`
+}
+
+func TestValidator_ContextValidatable(t *testing.T) {
+	v, _ := New("en")
+	ctx := context.WithValue(context.Background(), userKey{}, "root")
+
+	if err := v.ValidateCtx(ctx, &Account{Name: "root"}); err == nil || err.Error() != `name "root" is taken` {
+		t.Errorf("Expected ctx passed to Validate(ctx), got %v", err)
+	}
+	// Validate 以 context.Background() 调用
+	if err := v.Validate(&Account{Name: "root"}); err != nil {
+		t.Errorf("Expected no error without ctx value, got %v", err)
+	}
+
+	team := &Team{Owner: Account{Name: "alice"}, Members: []Account{{Name: "root"}, {}}}
+	ve, ok := v.ValidateDeepCtx(ctx, team).(*ValidationError)
+	if !ok {
+		t.Fatalf("Expected *ValidationError")
+	}
+	want := []string{"members[1].name", "members[0]"}
+	if len(ve.Fields) != len(want) {
+		t.Fatalf("Expected %v, got %+v", want, ve.Fields)
+	}
+	for i, path := range want {
+		if ve.Fields[i].Path != path {
+			t.Errorf("Expected field %d at %s, got %+v", i, path, ve.Fields[i])
+		}
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := v.ValidateCtx(canceled, &Account{Name: "bob"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if err := v.ValidateDeepCtx(canceled, team); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled from deep validation, got %v", err)
+	}
+}
+
+func TestValidator_RegisterRuleCtx(t *testing.T) {
+	v, _ := New("en")
+	err := v.RegisterRuleCtx("allowed", func(ctx context.Context, fl FieldLevel) bool {
+		allowed, _ := ctx.Value(userKey{}).(string)
+		return fl.Field().String() == allowed
+	}, map[string]string{"en": "{0} is not allowed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type Login struct {
+		User string `json:"user" validate:"allowed"`
+	}
+	ctx := context.WithValue(context.Background(), userKey{}, "alice")
+	if err := v.ValidateCtx(ctx, &Login{User: "alice"}); err != nil {
+		t.Errorf("Expected rule to read ctx, got %v", err)
+	}
+	ve, _ := v.ValidateCtx(ctx, &Login{User: "bob"}).(*ValidationError)
+	if ve == nil || ve.Errors["user"] != "user is not allowed" {
+		t.Errorf("Expected translated rule error, got %v", ve)
+	}
+
+	// 超时导致的规则失败报告为 ctx.Err()
+	slow, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	v.RegisterRuleCtx("slow", func(ctx context.Context, fl FieldLevel) bool {
+		<-ctx.Done()
+		return false
+	}, nil)
+	type Lookup struct {
+		Host string `json:"host" validate:"slow"`
+	}
+	if err := v.ValidateCtx(slow, &Lookup{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
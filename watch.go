@@ -0,0 +1,249 @@
+package conf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDebounce 合并编辑器保存时产生的连续文件事件
+const reloadDebounce = 100 * time.Millisecond
+
+// Watcher 持有当前生效的配置，并在配置文件变化时自动重新加载
+//
+// 每次重新加载都会完整执行 Load 的流程 (默认值、严格解码、Env Strict、验证)，
+// 只有全部通过才会替换当前配置；失败时保留旧配置，并通过 OnError 回调报告错误。
+type Watcher[T any] struct {
+	appName string
+	opts    *options
+	current atomic.Pointer[T]
+
+	fsw    *fsnotify.Watcher
+	done   chan struct{}
+	closed sync.Once
+
+	reloadMu sync.Mutex // 串行化重新加载
+
+	filesMu sync.Mutex
+	files   map[string]string // 当前加载的配置文件 -> 解析符号链接后的实际路径
+
+	mu       sync.RWMutex
+	onError  []func(error)
+	onChange []func(old, new *T, changes []Change)
+}
+
+// Watch 加载配置并开始监听配置文件变化
+// 首次加载失败时直接返回错误，不会启动监听
+func Watch[T any](appName string, opts ...Option) (*Watcher[T], error) {
+	o := newOptions(opts)
+
+	cfg, state, err := load[T](context.Background(), appName, o)
+	if err != nil {
+		return nil, err
+	}
+
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("create file watcher: %w", err)
+	}
+
+	// 监听目录而不是文件：编辑器常通过 rename 替换文件，直接监听文件会在第一次替换后失效；
+	// 同时也能感知启动后才创建的配置文件。Kubernetes ConfigMap 通过替换 ..data 符号链接更新，
+	// 配置文件本身不产生事件，因此还需比较配置文件解析符号链接后的实际路径 (见 targetChanged)
+	dirs := o.searchPaths
+	if o.configFile != "" {
+		dirs = []string{filepath.Dir(o.configFile)}
+	}
+
+	watched := 0
+	for _, path := range dirs {
+		dir, err := filepath.Abs(path)
+		if err != nil {
+			continue
+		}
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			continue
+		}
+		if err := fsw.Add(dir); err != nil {
+			fsw.Close()
+			return nil, fmt.Errorf("watch config dir %s: %w", dir, err)
+		}
+		watched++
+	}
+	if watched == 0 {
+		fsw.Close()
+		return nil, errors.New("watch config: no existing search path to watch")
+	}
+
+	w := &Watcher[T]{
+		appName: appName,
+		opts:    o,
+		fsw:     fsw,
+		done:    make(chan struct{}),
+	}
+	w.current.Store(cfg)
+	w.recordFiles(state.files)
+
+	go w.run()
+	return w, nil
+}
+
+// Get 返回当前生效的配置，可并发调用
+// 返回的配置应视为只读
+func (w *Watcher[T]) Get() *T {
+	return w.current.Load()
+}
+
+// OnError 注册重新加载失败时的回调
+// 回调在监听协程中同步执行，不应长时间阻塞
+func (w *Watcher[T]) OnError(fn func(err error)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onError = append(w.onError, fn)
+}
+
+// OnChange 注册配置变化回调
+// 只有新配置通过全部检查并生效、且至少一个配置项发生变化时才会触发，
+// changes 按结构体声明顺序列出每个变化的叶子配置项
+func (w *Watcher[T]) OnChange(fn func(old, new *T, changes []Change)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onChange = append(w.onChange, fn)
+}
+
+// Reload 立即重新加载配置 (例如收到 SIGHUP 时)
+// 失败时保留旧配置，错误既会返回也会通知 OnError 回调
+func (w *Watcher[T]) Reload() error {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	cfg, state, err := load[T](context.Background(), w.appName, w.opts)
+	if err != nil {
+		err = fmt.Errorf("reload config: %w", err)
+		w.notifyError(err)
+		return err
+	}
+	w.recordFiles(state.files)
+
+	old := w.current.Swap(cfg)
+	if changes := diffConfig(old, cfg); len(changes) > 0 {
+		w.notifyChange(old, cfg, changes)
+	}
+	return nil
+}
+
+// Close 停止监听，之后 Get 仍返回最后一次生效的配置
+func (w *Watcher[T]) Close() error {
+	var err error
+	w.closed.Do(func() {
+		close(w.done)
+		err = w.fsw.Close()
+	})
+	return err
+}
+
+func (w *Watcher[T]) run() {
+	var timer *time.Timer
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	for {
+		select {
+		case <-w.done:
+			return
+
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			if event.Op == fsnotify.Chmod || !w.isConfigFile(event.Name) && !w.targetChanged() {
+				continue
+			}
+			if timer == nil {
+				timer = time.AfterFunc(reloadDebounce, func() {
+					select {
+					case <-w.done:
+					default:
+						w.Reload()
+					}
+				})
+			} else {
+				timer.Reset(reloadDebounce)
+			}
+
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			w.notifyError(fmt.Errorf("watch config: %w", err))
+		}
+	}
+}
+
+// isConfigFile 判断事件对应的文件是否为基础配置或 profile 配置文件 (忽略扩展名)
+func (w *Watcher[T]) isConfigFile(path string) bool {
+	if w.opts.configFile != "" {
+		return filepath.Base(path) == filepath.Base(w.opts.configFile)
+	}
+
+	base := filepath.Base(path)
+	stem := strings.TrimSuffix(base, filepath.Ext(base))
+	return stem == w.opts.fileName || strings.HasPrefix(stem, w.opts.fileName+".")
+}
+
+// recordFiles 记录本次加载的配置文件及其解析符号链接后的实际路径
+func (w *Watcher[T]) recordFiles(files []string) {
+	targets := make(map[string]string, len(files))
+	for _, path := range files {
+		if target, err := filepath.EvalSymlinks(path); err == nil {
+			targets[path] = target
+		}
+	}
+
+	w.filesMu.Lock()
+	defer w.filesMu.Unlock()
+	w.files = targets
+}
+
+// targetChanged 判断是否有配置文件的符号链接指向了新的实际文件 (如 ConfigMap 替换 ..data)，
+// 与 Viper 的 WatchConfig 相同，发现变化时立即更新记录，避免同一次替换重复触发
+func (w *Watcher[T]) targetChanged() bool {
+	w.filesMu.Lock()
+	defer w.filesMu.Unlock()
+
+	changed := false
+	for path, old := range w.files {
+		if target, err := filepath.EvalSymlinks(path); err == nil && target != old {
+			w.files[path] = target
+			changed = true
+		}
+	}
+	return changed
+}
+
+func (w *Watcher[T]) notifyError(err error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	for _, fn := range w.onError {
+		fn(err)
+	}
+}
+
+func (w *Watcher[T]) notifyChange(old, new *T, changes []Change) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	for _, fn := range w.onChange {
+		fn(old, new, changes)
+	}
+}
@@ -0,0 +1,125 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// waitFor 轮询直到条件满足或超时
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatal("Timed out waiting for condition")
+}
+
+func TestWatch_ReloadOnChange(t *testing.T) {
+	configDir := createConfigFile(t, "config.yaml", "database:\n  host: \"a\"\n")
+	path := filepath.Join(configDir, "config.yaml")
+
+	w, err := Watch[TestConfig]("myapp", WithSearchPaths(configDir))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer w.Close()
+
+	if got := w.Get().Database.Host; got != "a" {
+		t.Fatalf("Expected initial host 'a', got '%s'", got)
+	}
+
+	if err := os.WriteFile(path, []byte("database:\n  host: \"b\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	waitFor(t, func() bool { return w.Get().Database.Host == "b" })
+}
+
+func TestWatch_RejectInvalidReload(t *testing.T) {
+	configDir := createConfigFile(t, "config.yaml", "database:\n  host: \"a\"\n")
+	path := filepath.Join(configDir, "config.yaml")
+
+	w, err := Watch[TestConfig]("myapp", WithSearchPaths(configDir), WithLocale("en"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer w.Close()
+
+	errs := make(chan error, 4)
+	w.OnError(func(err error) { errs <- err })
+
+	// port 不满足 min=1024，新配置应被拒绝
+	if err := os.WriteFile(path, []byte("database:\n  host: \"b\"\n  port: 80\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-errs:
+		if !strings.Contains(err.Error(), "database.port") {
+			t.Errorf("Expected validation error for port, got: %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("Expected reload error")
+	}
+
+	if got := w.Get().Database.Host; got != "a" {
+		t.Errorf("Expected previous config to be kept, got host '%s'", got)
+	}
+}
+
+func TestWatch_InitialLoadError(t *testing.T) {
+	configDir := createConfigFile(t, "config.yaml", "database:\n  port: 80\n")
+
+	if _, err := Watch[TestConfig]("myapp", WithSearchPaths(configDir)); err == nil {
+		t.Fatal("Expected initial load error")
+	}
+}
+
+func TestWatch_ConfigMapSymlinkSwap(t *testing.T) {
+	// 模拟 Kubernetes ConfigMap 的挂载布局:
+	// config.yaml -> ..data/config.yaml, ..data -> ..v1
+	dir := t.TempDir()
+	writeVersion := func(version, host string) {
+		if err := os.Mkdir(filepath.Join(dir, version), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, version, "config.yaml"), []byte("database:\n  host: \""+host+"\"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeVersion("..v1", "a")
+	if err := os.Symlink("..v1", filepath.Join(dir, "..data")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join("..data", "config.yaml"), filepath.Join(dir, "config.yaml")); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := Watch[TestConfig]("myapp", WithSearchPaths(dir))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer w.Close()
+
+	if got := w.Get().Database.Host; got != "a" {
+		t.Fatalf("Expected initial host 'a', got '%s'", got)
+	}
+
+	// 与 kubelet 相同: 写入新版本目录，再通过 rename 原子替换 ..data
+	writeVersion("..v2", "b")
+	if err := os.Symlink("..v2", filepath.Join(dir, "..data_tmp")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")); err != nil {
+		t.Fatal(err)
+	}
+
+	waitFor(t, func() bool { return w.Get().Database.Host == "b" })
+}